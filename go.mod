@@ -3,13 +3,17 @@ module github.com/TechMDW/hashit
 go 1.22
 
 require (
+	github.com/cespare/xxhash/v2 v2.3.0
 	github.com/joho/godotenv v1.5.1
+	github.com/jzelinskie/whirlpool v0.0.0-20201016144138-0675e54bb004
+	github.com/klauspost/cpuid/v2 v2.2.10
 	github.com/spf13/cobra v1.8.0
+	github.com/spf13/pflag v1.0.5
+	github.com/zeebo/xxh3 v1.1.0
 	golang.org/x/crypto v0.23.0
 )
 
 require (
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
-	github.com/spf13/pflag v1.0.5 // indirect
-	golang.org/x/sys v0.20.0 // indirect
+	golang.org/x/sys v0.30.0 // indirect
 )
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/TechMDW/hashit/pkg/hash"
+	"github.com/spf13/cobra"
+)
+
+var benchCmd = &cobra.Command{
+	Use:   "bench",
+	Short: "Measure the throughput of the hash functions",
+	Long: `Measure the throughput in MB/s (10^6 bytes per second) and the mean latency per
+call of each selected algorithm for every input size. A call creates a hash,
+writes the input and computes the digest. --multi also measures all selected
+algorithms computed together, as the default multi-hash mode does.
+
+The CPU and the instruction set extensions used by hash implementations, such
+as AVX2, SHA-NI and ARM SHA2, are reported with the results. --json output
+includes the time and Go version for tracking results over time.`,
+	Example:       "  hashit bench\n  hashit bench -t sha2,blake --sizes 1K,1M\n  hashit bench -t fast --multi --duration 1s --json",
+	RunE:          benchRun,
+	Args:          cobra.NoArgs,
+	SilenceErrors: true,
+	SilenceUsage:  true,
+}
+
+// benchReport is the JSON output of the bench command.
+type benchReport struct {
+	Time      time.Time          `json:"time"`
+	GoVersion string             `json:"goVersion"`
+	OS        string             `json:"os"`
+	CPU       hash.CPUInfo       `json:"cpu"`
+	Results   []hash.BenchResult `json:"results"`
+}
+
+func benchRun(cmd *cobra.Command, args []string) error {
+	var opts hash.BenchOptions
+	if hashType, _ := cmd.Flags().GetString("type"); hashType != "" {
+		opts.Algorithms = []string{hashType}
+	}
+	opts.Duration, _ = cmd.Flags().GetDuration("duration")
+	opts.Multi, _ = cmd.Flags().GetBool("multi")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	sizes, _ := cmd.Flags().GetStringSlice("sizes")
+	for _, s := range sizes {
+		size, err := hash.ParseSize(s)
+		if err != nil {
+			return err
+		}
+		if size == 0 {
+			return fmt.Errorf("benchmark input size must not be zero")
+		}
+		opts.Sizes = append(opts.Sizes, size)
+	}
+
+	cpu := hash.DetectCPU()
+	if !jsonOutput {
+		cmd.Printf("CPU: %s (%s, %d cores)\n", cpu.Brand, cpu.Arch, cpu.Cores)
+		cmd.Printf("Features: %s\n\n", dashIfEmpty(strings.Join(cpu.Features, " ")))
+	}
+
+	report := benchReport{Time: time.Now().UTC(), GoVersion: runtime.Version(), OS: runtime.GOOS, CPU: cpu}
+	// A canceled run still reports the measurements that completed.
+	results, runErr := hash.Bench(cmd.Context(), opts)
+	if runErr != nil && !isCanceled(runErr) {
+		return runErr
+	}
+	report.Results = results
+
+	if jsonOutput {
+		j, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		cmd.Println(string(j))
+		return runErr
+	}
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ALGORITHM\tSIZE\tMB/s\tLATENCY")
+	for _, res := range results {
+		fmt.Fprintf(w, "%s\t%s\t%.1f\t%s\n", res.Algorithm, hash.FormatSize(res.Size), res.MBPerSec, res.Latency)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	return runErr
+}
+
+func init() {
+	benchCmd.Flags().StringP("type", "t", "", "Hash functions to measure: name, comma separated list, family or group (default all)")
+	benchCmd.Flags().StringSlice("sizes", []string{"64", "1K", "64K", "1M"}, "Input sizes in bytes, with an optional K, M or G suffix")
+	benchCmd.Flags().Duration("duration", 100*time.Millisecond, "Minimum time spent measuring each algorithm and size")
+	benchCmd.Flags().Bool("multi", false, "Also measure all selected algorithms computed together")
+	benchCmd.Flags().BoolP("json", "j", false, "Output the results as JSON")
+	rootCmd.AddCommand(benchCmd)
+}
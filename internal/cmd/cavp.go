@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+
+	"github.com/TechMDW/hashit/pkg/hash"
+	"github.com/spf13/cobra"
+)
+
+var cavpCmd = &cobra.Command{
+	Use:   "cavp FILE.rsp...",
+	Short: "Check NIST CAVP response files",
+	Long: `Check the vectors of NIST CAVP response files (.rsp) for SHA-1, SHA-2, SHA-3
+and SHAKE: short messages, long messages, SHAKE variable output and Monte
+Carlo tests. The algorithm is taken from --type, the file name (e.g.
+SHA3_256ShortMsg.rsp) or the header of the file. The exit status is non-zero
+if any vector fails.`,
+	Example:       "  hashit cavp SHA256ShortMsg.rsp SHA256LongMsg.rsp SHA256Monte.rsp\n  hashit cavp --quiet shabytetestvectors/*.rsp\n  hashit cavp -t sha512_224 vectors.rsp",
+	RunE:          cavpRun,
+	Args:          cobra.MinimumNArgs(1),
+	SilenceErrors: true,
+	SilenceUsage:  true,
+}
+
+// cavpFileResult is the JSON output for one response file.
+type cavpFileResult struct {
+	File string `json:"file"`
+	*hash.CAVPReport
+	Error string `json:"error,omitempty"`
+}
+
+func cavpRun(cmd *cobra.Command, args []string) error {
+	hashType, _ := cmd.Flags().GetString("type")
+	quiet, _ := cmd.Flags().GetBool("quiet")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	var files []cavpFileResult
+	var runErr error
+	failed := false
+	for _, path := range args {
+		// A canceled file still reports the vectors that were checked.
+		var fr cavpFileResult
+		fr, runErr = cavpFile(cmd.Context(), path, hashType)
+		if fr.Error != "" {
+			failed = true
+			if !jsonOutput {
+				cmd.PrintErrf("hashit: %s: %s\n", path, fr.Error)
+			}
+			files = append(files, fr)
+			continue
+		}
+
+		passed := 0
+		for _, res := range fr.Results {
+			if res.Passed {
+				passed++
+			}
+			if jsonOutput {
+				continue
+			}
+			switch {
+			case res.Error != "":
+				cmd.Printf("%s:%d: %s: FAILED: %s\n", path, res.Line, res.Vector, res.Error)
+			case !res.Passed:
+				cmd.Printf("%s:%d: %s: FAILED: expected %s, got %s\n", path, res.Line, res.Vector, res.Expected, res.Got)
+			case !quiet:
+				cmd.Printf("%s:%d: %s: OK\n", path, res.Line, res.Vector)
+			}
+		}
+		if passed < len(fr.Results) {
+			failed = true
+		}
+		if !jsonOutput {
+			cmd.Printf("%s: %s: %d passed, %d failed\n", path, fr.Algorithm, passed, len(fr.Results)-passed)
+		}
+		files = append(files, fr)
+		if runErr != nil {
+			break
+		}
+	}
+
+	if jsonOutput {
+		j, err := json.MarshalIndent(files, "", "  ")
+		if err != nil {
+			return err
+		}
+		cmd.Println(string(j))
+	}
+
+	if runErr != nil {
+		return runErr
+	}
+	if failed {
+		return exitCode(1)
+	}
+
+	return nil
+}
+
+// cavpFile runs the vectors of one response file. It returns an error only
+// when the run was canceled, together with the vectors checked so far.
+func cavpFile(ctx context.Context, path, hashType string) (cavpFileResult, error) {
+	fr := cavpFileResult{File: path}
+
+	if hashType == "" {
+		// Fall back to the header of the file when the name does not
+		// tell the algorithm.
+		hashType, _ = hash.CAVPAlgorithm(path)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		fr.Error = err.Error()
+		return fr, nil
+	}
+	defer file.Close()
+
+	fr.CAVPReport, err = hash.RunCAVP(ctx, file, hashType)
+	if isCanceled(err) {
+		return fr, err
+	}
+	if err != nil {
+		fr.Error = err.Error()
+	}
+	return fr, nil
+}
+
+func init() {
+	cavpCmd.Flags().StringP("type", "t", "", "Hash type of the vectors (default: from the file name or header)")
+	cavpCmd.Flags().Bool("quiet", false, "Only print failed vectors and the summary")
+	cavpCmd.Flags().BoolP("json", "j", false, "Output the results as JSON")
+	rootCmd.AddCommand(cavpCmd)
+}
@@ -0,0 +1,235 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/TechMDW/hashit/pkg/hash"
+	"github.com/spf13/cobra"
+)
+
+var checkCmd = &cobra.Command{
+	Use:   "check [SUMSFILE]...",
+	Short: "Verify files against a checksum list",
+	Long: `Read checksums from the SUMSFILEs (or stdin) and verify them, like sha256sum --check.
+
+Both GNU coreutils ("<hex>  <file>", "<hex> *<file>") and BSD tag
+("SHA256 (<file>) = <hex>") lines are understood. The hash type is taken from
+the BSD tag, from --type, or detected from the digest length.`,
+	Example:       "  hashit check SHA256SUMS\n  hashit check --ignore-missing --quiet SHA256SUMS\n  sha256sum * | hashit check",
+	RunE:          checkRun,
+	SilenceErrors: true,
+	SilenceUsage:  true,
+}
+
+// checkOptions holds the flags of the check command.
+type checkOptions struct {
+	hashType      string
+	quiet         bool
+	status        bool
+	ignoreMissing bool
+	strict        bool
+	warn          bool
+	// warned records the broken algorithms already warned about, so that
+	// each is reported once per run.
+	warned map[string]bool
+}
+
+// checkResult counts the outcomes of verifying one checksum file.
+type checkResult struct {
+	lines        int
+	formatted    int
+	misformatted int
+	mismatched   int
+	unreadable   int
+	verified     int
+}
+
+func checkRun(cmd *cobra.Command, args []string) error {
+	var opts checkOptions
+	opts.hashType, _ = cmd.Flags().GetString("type")
+	opts.quiet, _ = cmd.Flags().GetBool("quiet")
+	opts.status, _ = cmd.Flags().GetBool("status")
+	opts.ignoreMissing, _ = cmd.Flags().GetBool("ignore-missing")
+	opts.strict, _ = cmd.Flags().GetBool("strict")
+	opts.warn, _ = cmd.Flags().GetBool("warn")
+	opts.warned = make(map[string]bool)
+
+	if len(args) == 0 {
+		args = []string{"-"}
+	}
+
+	failed := false
+	for _, sumsFile := range args {
+		ok, err := checkFile(cmd, sumsFile, opts)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			failed = true
+		}
+	}
+
+	if failed {
+		return exitCode(1)
+	}
+
+	return nil
+}
+
+// checkFile verifies every line of sumsFile and reports whether all of them
+// passed. It returns an error only when checking was canceled.
+func checkFile(cmd *cobra.Command, sumsFile string, opts checkOptions) (bool, error) {
+	var r io.Reader = os.Stdin
+	if sumsFile != "-" {
+		file, err := os.Open(sumsFile)
+		if err != nil {
+			cmd.PrintErrf("hashit: %s\n", err)
+			return false, nil
+		}
+		defer file.Close()
+		r = file
+	}
+
+	var res checkResult
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		res.lines++
+		line := scanner.Text()
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		cl, err := hash.ParseChecksumLine(line)
+		if err == nil {
+			formatted, err := checkLine(cmd, cl, opts, &res)
+			if err != nil {
+				return false, err
+			}
+			if formatted {
+				res.formatted++
+				continue
+			}
+		}
+
+		res.misformatted++
+		if opts.warn {
+			cmd.PrintErrf("hashit: %s: %d: improperly formatted checksum line\n", sumsFile, res.lines)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		cmd.PrintErrf("hashit: %s: %s\n", sumsFile, err)
+		return false, nil
+	}
+
+	if res.formatted == 0 {
+		cmd.PrintErrf("hashit: %s: no properly formatted checksum lines found\n", sumsFile)
+		return false, nil
+	}
+
+	if !opts.status {
+		if res.misformatted > 0 {
+			cmd.PrintErrf("hashit: WARNING: %s improperly formatted\n", plural(res.misformatted, "line is", "lines are"))
+		}
+		if res.unreadable > 0 {
+			cmd.PrintErrf("hashit: WARNING: %s could not be read\n", plural(res.unreadable, "listed file", "listed files"))
+		}
+		if res.mismatched > 0 {
+			cmd.PrintErrf("hashit: WARNING: %s did NOT match\n", plural(res.mismatched, "computed checksum", "computed checksums"))
+		}
+	}
+
+	if opts.ignoreMissing && res.verified == 0 && res.mismatched == 0 && res.unreadable == 0 {
+		if !opts.status {
+			cmd.PrintErrf("hashit: %s: no file was verified\n", sumsFile)
+		}
+		return false, nil
+	}
+
+	return res.mismatched == 0 && res.unreadable == 0 && (!opts.strict || res.misformatted == 0), nil
+}
+
+// checkLine verifies a single parsed checksum line and records the outcome.
+// It returns false if the hash type of the line cannot be determined, and an
+// error only when hashing was canceled.
+func checkLine(cmd *cobra.Command, cl hash.ChecksumLine, opts checkOptions, res *checkResult) (bool, error) {
+	hashType := opts.hashType
+	if cl.Algorithm != "" {
+		hashType = cl.Algorithm
+	}
+	if hashType == "" {
+		var err error
+		hashType, err = hash.AlgorithmForDigest(cl.Digest)
+		if err != nil {
+			return false, nil
+		}
+	}
+
+	var gh *hash.GenericHash
+	var err error
+	if cl.Path == "-" {
+		gh, err = hash.ComputeHashReader(cmd.Context(), os.Stdin, hashType)
+	} else {
+		gh, err = hash.ComputeHash(cmd.Context(), []byte(cl.Path), hashType, true)
+	}
+	if err != nil {
+		if isCanceled(err) {
+			return false, err
+		}
+		if opts.ignoreMissing && errors.Is(err, fs.ErrNotExist) {
+			return true, nil
+		}
+
+		res.unreadable++
+		if !opts.status {
+			cmd.PrintErrf("hashit: %s\n", err)
+			cmd.Printf("%s: FAILED open or read\n", cl.Path)
+		}
+		return true, nil
+	}
+
+	if !opts.status && !opts.warned[gh.Algorithm] {
+		warnBroken(cmd, gh.Algorithm, false)
+		opts.warned[gh.Algorithm] = true
+	}
+
+	if gh.HexDigest != cl.Digest {
+		res.mismatched++
+		if !opts.status {
+			cmd.Printf("%s: FAILED\n", cl.Path)
+		}
+		return true, nil
+	}
+
+	res.verified++
+	if !opts.status && !opts.quiet {
+		cmd.Printf("%s: OK\n", cl.Path)
+	}
+
+	return true, nil
+}
+
+// plural formats n with the singular or plural form of a noun phrase.
+func plural(n int, singular, pluralForm string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, singular)
+	}
+
+	return fmt.Sprintf("%d %s", n, pluralForm)
+}
+
+func init() {
+	checkCmd.Flags().StringP("type", "t", "", "Hash type to assume for lines without a BSD tag")
+	checkCmd.Flags().Bool("quiet", false, "Don't print OK for each successfully verified file")
+	checkCmd.Flags().Bool("status", false, "Don't output anything, status code shows success")
+	checkCmd.Flags().Bool("ignore-missing", false, "Don't fail or report status for missing files")
+	checkCmd.Flags().Bool("strict", false, "Exit non-zero for improperly formatted checksum lines")
+	checkCmd.Flags().BoolP("warn", "w", false, "Warn about improperly formatted checksum lines")
+	rootCmd.AddCommand(checkCmd)
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/TechMDW/hashit/pkg/hash"
+	"github.com/spf13/cobra"
+)
+
+// checkpointOptions returns the options given by --checkpoint, --resume and
+// --checkpoint-interval, and whether checkpoints were requested at all.
+func checkpointOptions(cmd *cobra.Command) (hash.CheckpointOptions, bool, error) {
+	var opts hash.CheckpointOptions
+	opts.Path, _ = cmd.Flags().GetString("checkpoint")
+	opts.Resume, _ = cmd.Flags().GetBool("resume")
+	opts.Interval, _ = cmd.Flags().GetDuration("checkpoint-interval")
+
+	if opts.Path == "" {
+		if opts.Resume || cmd.Flags().Changed("checkpoint-interval") {
+			return opts, false, fmt.Errorf("--resume and --checkpoint-interval need --checkpoint")
+		}
+		return opts, false, nil
+	}
+	if opts.Interval <= 0 {
+		return opts, false, fmt.Errorf("invalid checkpoint interval %s", opts.Interval)
+	}
+
+	return opts, true, nil
+}
+
+func init() {
+	rootCmd.Flags().String("checkpoint", "", "Periodically save the hash state of -f to this file so an interrupted run can be resumed")
+	rootCmd.Flags().Bool("resume", false, "Continue from the --checkpoint file if it exists")
+	rootCmd.Flags().Duration("checkpoint-interval", hash.DefaultCheckpointInterval, "Time between checkpoints")
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// hmacKey returns the MAC key given by --hmac-key, --hmac-key-file or
+// --hmac-key-env, and whether one was given at all.
+func hmacKey(cmd *cobra.Command) ([]byte, bool, error) {
+	key, _ := cmd.Flags().GetString("hmac-key")
+	keyFile, _ := cmd.Flags().GetString("hmac-key-file")
+	keyEnv, _ := cmd.Flags().GetString("hmac-key-env")
+
+	switch {
+	case cmd.Flags().Changed("hmac-key"):
+		return []byte(key), true, nil
+	case keyFile != "":
+		b, err := os.ReadFile(keyFile)
+		if err != nil {
+			return nil, false, err
+		}
+		return b, true, nil
+	case keyEnv != "":
+		value, ok := os.LookupEnv(keyEnv)
+		if !ok {
+			return nil, false, fmt.Errorf("environment variable %s is not set", keyEnv)
+		}
+		return []byte(value), true, nil
+	default:
+		return nil, false, nil
+	}
+}
+
+func init() {
+	rootCmd.Flags().String("hmac-key", "", "Compute a keyed hash (HMAC, KMAC or keyed BLAKE2) with this key")
+	rootCmd.Flags().String("hmac-key-file", "", "Read the MAC key from a file")
+	rootCmd.Flags().String("hmac-key-env", "", "Read the MAC key from an environment variable")
+	rootCmd.Flags().String("verify", "", "Compare the result with this hex digest or MAC and exit non-zero on mismatch")
+	rootCmd.Flags().String("derive-key", "", "Derive key material from the input with BLAKE3 in this context")
+	rootCmd.MarkFlagsMutuallyExclusive("hmac-key", "hmac-key-file", "hmac-key-env")
+}
@@ -1,25 +1,121 @@
 package cmd
 
 import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
 	"github.com/TechMDW/hashit/pkg/hash"
+	"github.com/TechMDW/hashit/pkg/hash/crc"
 	"github.com/spf13/cobra"
 )
 
 var listHashesCmd = &cobra.Command{
 	Use:   "list-hashes",
 	Short: "List all available hash functions",
-	Long:  `List all available hash functions that can be used with the hash command.`,
-	Run:   listHashesRun,
+	Long: `List all available hash functions that can be used with the hash command.
+
+--long and --json add the family, digest and block size in bytes, the
+supported modes and the security status of each algorithm: secure, legacy
+(superseded), broken (practical collision attacks) or non-cryptographic.`,
+	Example:       "  hashit list-hashes\n  hashit list-hashes --long\n  hashit list-hashes --json\n  hashit list-hashes --crc",
+	RunE:          listHashesRun,
+	SilenceErrors: true,
+	SilenceUsage:  true,
+}
+
+func listHashesRun(cmd *cobra.Command, args []string) error {
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+	long, _ := cmd.Flags().GetBool("long")
+
+	if crcs, _ := cmd.Flags().GetBool("crc"); crcs {
+		if jsonOutput || long {
+			return fmt.Errorf("--crc cannot be combined with --json or --long")
+		}
+		cmd.Println("CRC catalogue:")
+		for _, p := range crc.Catalogue {
+			if len(p.Aliases) > 0 {
+				cmd.Printf("- %s (%s)\n", p.Name, strings.Join(p.Aliases, ", "))
+				continue
+			}
+			cmd.Println("-", p.Name)
+		}
+		return nil
+	}
+
+	algos := hash.Algorithms()
+
+	switch {
+	case jsonOutput:
+		j, err := json.MarshalIndent(algos, "", "  ")
+		if err != nil {
+			return err
+		}
+		cmd.Println(string(j))
+	case long:
+		printAlgorithmTable(cmd, algos)
+	default:
+		cmd.Println("Available hash functions:")
+		for _, algo := range algos {
+			if len(algo.Aliases) > 0 {
+				cmd.Printf("- %s (%s)\n", algo.Name, strings.Join(algo.Aliases, ", "))
+				continue
+			}
+			cmd.Println("-", algo.Name)
+		}
+	}
+
+	return nil
+}
+
+// printAlgorithmTable prints one line of metadata per algorithm.
+func printAlgorithmTable(cmd *cobra.Command, algos []hash.Algorithm) {
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tFAMILY\tSIZE\tBLOCK\tCRYPTO\tMODES\tALIASES\tSECURITY")
+	for _, a := range algos {
+		var modes []string
+		if a.NewKeyed != nil {
+			modes = append(modes, "keyed")
+		}
+		if a.NewSeeded != nil {
+			modes = append(modes, "seeded")
+		}
+		if a.NewXOF != nil {
+			modes = append(modes, "xof")
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%s\t%s\t%s\t%s\n", a.Name, a.Family, a.Size, a.BlockSize,
+			yesNo(a.Cryptographic()), dashIfEmpty(strings.Join(modes, ",")), dashIfEmpty(strings.Join(a.Aliases, ",")), securityText(a))
+	}
+	w.Flush()
+}
+
+// securityText returns the security status followed by its note, if any.
+func securityText(a hash.Algorithm) string {
+	if a.SecurityNote == "" {
+		return string(a.Security)
+	}
+	return fmt.Sprintf("%s (%s)", a.Security, a.SecurityNote)
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
 }
 
-func listHashesRun(cmd *cobra.Command, args []string) {
-	availableHashes := hash.ComputeHashList()
-	cmd.Println("Available hash functions:")
-	for _, hashType := range availableHashes {
-		cmd.Println("-", hashType)
+func dashIfEmpty(s string) string {
+	if s == "" {
+		return "-"
 	}
+	return s
 }
 
 func init() {
+	listHashesCmd.Flags().Bool("crc", false, "List the CRC catalogue usable with -t, e.g. -t crc-16/xmodem")
+	listHashesCmd.Flags().BoolP("json", "j", false, "Output the algorithms and their metadata as JSON")
+	listHashesCmd.Flags().BoolP("long", "l", false, "Show family, sizes, supported modes and security status")
 	rootCmd.AddCommand(listHashesCmd)
 }
@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/TechMDW/hashit/pkg/hash"
+	"github.com/spf13/cobra"
+)
+
+// defaultManifestHash is used for manifests when no hash type is given.
+const defaultManifestHash = "sha256"
+
+// singleInputFlags are the root flags that only apply to a single input.
+// They are rejected with -r rather than silently ignored.
+var singleInputFlags = []string{
+	"file",
+	"json",
+	"progress",
+	"hmac-key",
+	"hmac-key-file",
+	"hmac-key-env",
+	"verify",
+	"derive-key",
+	"seed",
+	"length",
+	"function-name",
+	"customization",
+	"crc-params",
+	"checkpoint",
+	"resume",
+	"checkpoint-interval",
+	"offset",
+	"range-length",
+	"skip-tail",
+	"quick",
+}
+
+// hashPaths hashes the files and directories in args and writes a manifest.
+func hashPaths(cmd *cobra.Command, args []string) error {
+	for _, name := range singleInputFlags {
+		if cmd.Flags().Changed(name) {
+			return fmt.Errorf("--%s cannot be combined with -r", name)
+		}
+	}
+
+	hashType, _ := cmd.Flags().GetString("type")
+	formatName, _ := cmd.Flags().GetString("format")
+	symlinks, _ := cmd.Flags().GetString("symlinks")
+	workers, _ := cmd.Flags().GetInt("workers")
+
+	var opts hash.WalkOptions
+	opts.Recursive = true
+	opts.Include, _ = cmd.Flags().GetStringArray("include")
+	opts.Exclude, _ = cmd.Flags().GetStringArray("exclude")
+	opts.GitIgnore, _ = cmd.Flags().GetBool("gitignore")
+
+	format, err := hash.ParseManifestFormat(formatName)
+	if err != nil {
+		return err
+	}
+
+	opts.Symlinks, err = hash.ParseSymlinkPolicy(symlinks)
+	if err != nil {
+		return err
+	}
+
+	if hashType == "" {
+		hashType = defaultManifestHash
+	}
+
+	files, err := hash.CollectFiles(cmd.Context(), args, opts)
+	if err != nil {
+		return err
+	}
+
+	entries, err := hash.HashFiles(cmd.Context(), files, []string{hashType}, workers)
+	if err != nil {
+		return err
+	}
+
+	return writeManifest(cmd, entries, format)
+}
+
+// hashInputManifest hashes a single input and writes it as a one line
+// manifest, so that e.g. -t cksum --format native prints what cksum would.
+// path is "-" for stdin and string arguments.
+func hashInputManifest(cmd *cobra.Command, r io.Reader, path string, hashType string) error {
+	formatName, _ := cmd.Flags().GetString("format")
+	format, err := hash.ParseManifestFormat(formatName)
+	if err != nil {
+		return err
+	}
+
+	if hashType == "" {
+		hashType = defaultManifestHash
+	}
+
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		r = file
+	}
+
+	counter := &countingReader{r: r}
+	hashes, err := hash.HashReader(cmd.Context(), counter, hashType)
+	if err != nil {
+		return err
+	}
+
+	entries := []hash.ManifestEntry{{Path: path, Size: counter.n, Hashes: hashes}}
+	return writeManifest(cmd, entries, format)
+}
+
+// writeManifest applies --encoding to the entries and writes them, then
+// reports the files that could not be hashed.
+func writeManifest(cmd *cobra.Command, entries []hash.ManifestEntry, format hash.ManifestFormat) error {
+	enc, err := outputEncoding(cmd)
+	if err != nil {
+		return err
+	}
+	// The native lines are those of the original tools, which print
+	// decimal checksums and hex digests only.
+	if enc != nil && format == hash.ManifestNative {
+		return fmt.Errorf("--encoding cannot be combined with --format native")
+	}
+	if enc != nil {
+		for i := range entries {
+			if entries[i].Err != nil {
+				continue
+			}
+			if err := entries[i].Hashes.Encode(enc); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := hash.WriteManifest(cmd.OutOrStdout(), entries, format); err != nil {
+		return err
+	}
+
+	failed := false
+	for _, entry := range entries {
+		if entry.Err != nil {
+			cmd.PrintErrf("hashit: %s: %s\n", entry.Path, entry.Err)
+			failed = true
+		}
+	}
+	if failed {
+		return exitCode(1)
+	}
+
+	return nil
+}
+
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+func init() {
+	rootCmd.Flags().BoolP("recursive", "r", false, "Hash files and directories given as arguments and print a manifest")
+	rootCmd.Flags().String("format", "coreutils", "Manifest format: coreutils, bsd, jsonl, csv or native (the line of cksum, sum -r or sum -s)")
+	rootCmd.Flags().StringArray("include", nil, "Only hash files matching this glob (repeatable)")
+	rootCmd.Flags().StringArray("exclude", nil, "Skip files and directories matching this glob (repeatable)")
+	rootCmd.Flags().Bool("gitignore", false, "Skip files ignored by .gitignore and .git directories")
+	rootCmd.Flags().String("symlinks", "skip", "Symbolic links inside directories: skip, follow or target")
+	rootCmd.Flags().Int("workers", 0, "Number of files hashed in parallel (default one per CPU)")
+}
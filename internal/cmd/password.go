@@ -0,0 +1,209 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/TechMDW/hashit/pkg/hash/password"
+	"github.com/spf13/cobra"
+)
+
+var passwordCmd = &cobra.Command{
+	Use:   "password",
+	Short: "Hash and verify passwords with bcrypt, scrypt, Argon2 or PBKDF2",
+	Long: `Hash and verify passwords with bcrypt, scrypt, Argon2i, Argon2id or PBKDF2.
+
+bcrypt hashes use the modular crypt format ($2a$...), all other algorithms the
+PHC string format ($argon2id$v=19$m=65536,t=3,p=4$<salt>$<key>). When the
+password argument is omitted it is read from the first line of stdin.`,
+}
+
+var passwordHashCmd = &cobra.Command{
+	Use:     "hash [PASSWORD]",
+	Short:   "Hash a password",
+	Example: "  hashit password hash hunter2\n  echo hunter2 | hashit password hash -a bcrypt --cost 10\n  hashit password hash -a scrypt --log-n 15 hunter2",
+	RunE:    interruptible(passwordHashRun),
+	Args:    cobra.MaximumNArgs(1),
+}
+
+var passwordVerifyCmd = &cobra.Command{
+	Use:     "verify HASH [PASSWORD]",
+	Short:   "Check a password against a hash",
+	Long:    "Check a password against a hash. Prints OK or FAILED and exits with status 1 on mismatch.",
+	Example: "  hashit password verify '$argon2id$v=19$m=65536,t=3,p=4$...' hunter2",
+	RunE:    interruptible(passwordVerifyRun),
+	Args:    cobra.RangeArgs(1, 2),
+}
+
+var passwordNeedsRehashCmd = &cobra.Command{
+	Use:   "needs-rehash HASH",
+	Short: "Report whether a hash uses other parameters than requested",
+	Long: `Report whether a hash was produced with another algorithm or other cost
+parameters than the ones given by the flags (the defaults unless set). Prints
+yes or no; the exit status is 0 for yes and 1 for no, so it can be used in
+shell conditions.`,
+	Example: "  hashit password needs-rehash -a argon2id '$2a$10$...'",
+	RunE:    interruptible(passwordNeedsRehashRun),
+	Args:    cobra.ExactArgs(1),
+}
+
+func passwordHashRun(cmd *cobra.Command, args []string) error {
+	algo, params, err := passwordParams(cmd)
+	if err != nil {
+		return err
+	}
+
+	pw, err := readPassword(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	encoded, err := password.Generate(pw, algo, params)
+	if err != nil {
+		return err
+	}
+
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+	if jsonOutput {
+		h, err := password.Parse(encoded)
+		if err != nil {
+			return err
+		}
+
+		j, err := json.MarshalIndent(struct {
+			Hash      string             `json:"hash"`
+			Algorithm password.Algorithm `json:"algorithm"`
+			Params    password.Params    `json:"params"`
+		}{encoded, h.Algorithm, h.Params}, "", "  ")
+		if err != nil {
+			return err
+		}
+		cmd.Println(string(j))
+		return nil
+	}
+
+	cmd.Println(encoded)
+	return nil
+}
+
+func passwordVerifyRun(cmd *cobra.Command, args []string) error {
+	pw, err := readPassword(cmd, args[1:])
+	if err != nil {
+		return err
+	}
+
+	ok, err := password.Verify(pw, args[0])
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		cmd.Println("FAILED")
+		return exitCode(1)
+	}
+
+	cmd.Println("OK")
+	return nil
+}
+
+func passwordNeedsRehashRun(cmd *cobra.Command, args []string) error {
+	algo, params, err := passwordParams(cmd)
+	if err != nil {
+		return err
+	}
+
+	rehash, err := password.NeedsRehash(args[0], algo, params)
+	if err != nil {
+		return err
+	}
+
+	if !rehash {
+		cmd.Println("no")
+		return exitCode(1)
+	}
+
+	cmd.Println("yes")
+	return nil
+}
+
+// passwordParams returns the algorithm selected by --algorithm and its
+// default parameters overridden by any cost flags that were set.
+func passwordParams(cmd *cobra.Command) (password.Algorithm, password.Params, error) {
+	name, _ := cmd.Flags().GetString("algorithm")
+	algo := password.Algorithm(strings.ToLower(name))
+
+	p, err := password.DefaultParams(algo)
+	if err != nil {
+		return "", p, err
+	}
+
+	flags := cmd.Flags()
+	if flags.Changed("cost") {
+		p.Cost, _ = flags.GetInt("cost")
+	}
+	if flags.Changed("log-n") {
+		p.LogN, _ = flags.GetInt("log-n")
+	}
+	if flags.Changed("block-size") {
+		p.BlockSize, _ = flags.GetInt("block-size")
+	}
+	if flags.Changed("time") {
+		p.Time, _ = flags.GetUint32("time")
+	}
+	if flags.Changed("memory") {
+		p.Memory, _ = flags.GetUint32("memory")
+	}
+	if flags.Changed("parallelism") {
+		p.Parallelism, _ = flags.GetInt("parallelism")
+	}
+	if flags.Changed("iterations") {
+		p.Iterations, _ = flags.GetInt("iterations")
+	}
+	if flags.Changed("salt-length") {
+		p.SaltLength, _ = flags.GetInt("salt-length")
+	}
+	if flags.Changed("key-length") {
+		p.KeyLength, _ = flags.GetInt("key-length")
+	}
+
+	return algo, p, nil
+}
+
+// readPassword returns the password argument, or the first line of stdin
+// when there is none.
+func readPassword(cmd *cobra.Command, args []string) ([]byte, error) {
+	if len(args) > 0 {
+		return []byte(args[0]), nil
+	}
+
+	line, err := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+	if err != nil && line == "" {
+		return nil, errors.New("no password given")
+	}
+
+	return []byte(strings.TrimRight(line, "\r\n")), nil
+}
+
+func addPasswordParamFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("algorithm", "a", string(password.Argon2id), "Algorithm: bcrypt, scrypt, argon2i, argon2id, pbkdf2-sha256 or pbkdf2-sha512")
+	cmd.Flags().Int("cost", 0, "bcrypt cost (default 12)")
+	cmd.Flags().Int("log-n", 0, "scrypt CPU/memory cost as log2(N) (default 17)")
+	cmd.Flags().Int("block-size", 0, "scrypt block size r (default 8)")
+	cmd.Flags().Uint32("time", 0, "Argon2 passes (default 3)")
+	cmd.Flags().Uint32("memory", 0, "Argon2 memory in KiB (default 65536)")
+	cmd.Flags().Int("parallelism", 0, "Argon2 lanes or scrypt p (default 4 for Argon2, 1 for scrypt)")
+	cmd.Flags().Int("iterations", 0, "PBKDF2 iterations (default 600000 for SHA-256, 210000 for SHA-512)")
+	cmd.Flags().Int("salt-length", 0, "Salt length in bytes (default 16)")
+	cmd.Flags().Int("key-length", 0, "Derived key length in bytes (default 32, 64 for PBKDF2-SHA512)")
+}
+
+func init() {
+	addPasswordParamFlags(passwordHashCmd)
+	passwordHashCmd.Flags().BoolP("json", "j", false, "Output as JSON")
+	addPasswordParamFlags(passwordNeedsRehashCmd)
+
+	passwordCmd.AddCommand(passwordHashCmd, passwordVerifyCmd, passwordNeedsRehashCmd)
+	rootCmd.AddCommand(passwordCmd)
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/TechMDW/hashit/pkg/hash"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// barInterval is the time between redraws of the progress bar.
+	barInterval = 200 * time.Millisecond
+	// eventInterval is the time between JSON progress events.
+	eventInterval = 5 * time.Second
+	barWidth      = 30
+)
+
+// progressReporter renders the progress of hashing one input on stderr: a
+// bar redrawn in place on a terminal, and JSON lines otherwise.
+type progressReporter struct {
+	w     io.Writer
+	input string
+	tty   bool
+	drawn bool
+}
+
+// progressEvent is a JSON progress line.
+type progressEvent struct {
+	Input string `json:"input"`
+	hash.Progress
+	Percent float64 `json:"percent"`
+}
+
+// withProgress makes the command report the progress of hashing input when
+// --progress is given. The returned function ends a bar left behind when
+// hashing stopped early.
+func withProgress(cmd *cobra.Command, input string) func() {
+	if progress, _ := cmd.Flags().GetBool("progress"); !progress {
+		return func() {}
+	}
+
+	r := &progressReporter{w: cmd.ErrOrStderr(), input: input, tty: stderrIsTerminal()}
+	interval := eventInterval
+	if r.tty {
+		interval = barInterval
+	}
+
+	cmd.SetContext(hash.WithProgress(cmd.Context(), r, interval))
+	return r.finish
+}
+
+func (r *progressReporter) Progress(p hash.Progress) {
+	if !r.tty {
+		j, err := json.Marshal(progressEvent{Input: r.input, Progress: p, Percent: p.Percent()})
+		if err == nil {
+			fmt.Fprintln(r.w, string(j))
+		}
+		return
+	}
+
+	var line string
+	if percent := p.Percent(); percent >= 0 {
+		filled := int(percent / 100 * barWidth)
+		line = fmt.Sprintf("[%s%s] %3.0f%%  %s / %s  %s/s", strings.Repeat("=", filled), strings.Repeat(" ", barWidth-filled),
+			percent, humanSize(p.Processed), humanSize(p.Total), humanSize(int64(p.Rate)))
+		if p.ETA > 0 {
+			line += "  ETA " + p.ETA.Round(time.Second).String()
+		}
+	} else {
+		line = fmt.Sprintf("%s  %s/s", humanSize(p.Processed), humanSize(int64(p.Rate)))
+	}
+
+	// \x1b[K clears what is left of a longer previous line.
+	fmt.Fprintf(r.w, "\r%s %s\x1b[K", r.input, line)
+	r.drawn = true
+	if p.Done {
+		r.finish()
+	}
+}
+
+// finish moves past the progress bar so later output starts on a new line.
+func (r *progressReporter) finish() {
+	if r.drawn {
+		fmt.Fprintln(r.w)
+		r.drawn = false
+	}
+}
+
+// humanSize formats n bytes with one decimal in the largest binary unit,
+// e.g. "1.5 GiB".
+func humanSize(n int64) string {
+	const units = "KMGTPE"
+	if n < 1024 {
+		return fmt.Sprintf("%d B", n)
+	}
+
+	value := float64(n)
+	i := -1
+	for value >= 1024 && i < len(units)-1 {
+		value /= 1024
+		i++
+	}
+	return fmt.Sprintf("%.1f %ciB", value, units[i])
+}
+
+// stderrIsTerminal reports whether stderr is attached to a terminal.
+func stderrIsTerminal() bool {
+	stat, err := os.Stderr.Stat()
+	if err != nil {
+		return false
+	}
+
+	return stat.Mode()&os.ModeCharDevice != 0
+}
@@ -1,101 +1,404 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/TechMDW/hashit/pkg/hash"
 	"github.com/spf13/cobra"
 )
 
 var rootCmd = &cobra.Command{
-	Use:     "hashit [string]",
-	Example: "  hashit \"Hello, World!\" \n  hashit \"Hello, World!\" -t md5 \n  hashit -f /path/to/file\n  hashit -f /path/to/file -t sha256",
+	Use:     "hashit [string | -r path...]",
+	Example: "  hashit \"Hello, World!\" \n  hashit \"Hello, World!\" -t md5 \n  hashit -f /path/to/file\n  hashit -f /path/to/file -t sha256\n  hashit -f /path/to/file -t sha256,blake2b512,crc32c\n  hashit \"Hello, World!\" -t sha2 -j\n  hashit \"Hello, World!\" -t shake256 --length 512bits\n  hashit -f /path/to/file -t sha256 --hmac-key-env MAC_KEY --verify <hex>\n  tar c dir | hashit -t sha256\n  hashit -f - < /path/to/file\n  hashit -r dir --gitignore > SHA256SUMS\n  hashit -r --format jsonl --exclude '*.log' dir1 dir2 'docs/*.md'",
 	Short:   "Hash a file using multiple hash functions",
 	Long:    `Hash a file using Adler, MD4, MD5, SHA1, SHA2, SHA3, FNV and CRC hash functions.`,
-	Run:     hashRun,
-	Args:    cobra.MaximumNArgs(1),
+	RunE:    hashRun,
+	Args:    cobra.ArbitraryArgs,
+
+	PersistentPreRunE: applyTimeout,
+
+	SilenceErrors: true,
+	SilenceUsage:  true,
 }
 
-func hashRun(cmd *cobra.Command, args []string) {
+func hashRun(cmd *cobra.Command, args []string) error {
+	if recursive, _ := cmd.Flags().GetBool("recursive"); recursive {
+		if len(args) == 0 {
+			return fmt.Errorf("no files or directories given")
+		}
+		return hashPaths(cmd, args)
+	}
+
+	if len(args) > 1 {
+		return fmt.Errorf("accepts at most 1 arg(s), received %d; use -r to hash several paths", len(args))
+	}
+
 	filePath, _ := cmd.Flags().GetString("file")
 	hashType, _ := cmd.Flags().GetString("type")
 	jsonOutput, _ := cmd.Flags().GetBool("json")
 
-	isFile := filePath != ""
+	if crcParams, _ := cmd.Flags().GetString("crc-params"); crcParams != "" {
+		if hashType != "" {
+			return fmt.Errorf("--crc-params cannot be combined with -t")
+		}
+		hashType = crcParams
+	}
+
+	isStdin := filePath == "-" || (filePath == "" && len(args) == 0 && stdinIsPipe())
+	isFile := filePath != "" && !isStdin
 	isArgs := len(args) > 0
 
-	if !isFile && !isArgs {
-		cmd.Help()
-		return
+	if !isFile && !isArgs && !isStdin {
+		return cmd.Help()
 	}
 
-	var data []byte
-	if isFile {
-		data = []byte(filePath)
-	} else {
-		data = []byte(args[0])
+	switch {
+	case isFile:
+		defer withProgress(cmd, filePath)()
+	case isStdin:
+		defer withProgress(cmd, "-")()
 	}
 
-	if hashType != "" {
-		hash, err := hash.ComputeHash(data, hashType, isFile)
+	key, keyed, err := hmacKey(cmd)
+	if err != nil {
+		return err
+	}
+	verify, _ := cmd.Flags().GetString("verify")
+	deriveContext, _ := cmd.Flags().GetString("derive-key")
+	derive := cmd.Flags().Changed("derive-key")
+
+	if derive {
+		if hashType == "" {
+			hashType = "blake3"
+		}
+		if algo, err := hash.Lookup(hashType); err != nil || algo.Name != "blake3" || keyed {
+			return fmt.Errorf("--derive-key needs -t blake3 and no MAC key")
+		}
+	}
+
+	seedValue, _ := cmd.Flags().GetString("seed")
+	seeded := cmd.Flags().Changed("seed")
+	var seed uint64
+	if seeded {
+		if keyed || derive {
+			return fmt.Errorf("--seed cannot be combined with a MAC key or --derive-key")
+		}
+		seed, err = strconv.ParseUint(seedValue, 0, 64)
 		if err != nil {
-			cmd.PrintErr(err)
-			return
+			return fmt.Errorf("invalid seed %q: %w", seedValue, err)
 		}
+	}
 
-		if jsonOutput {
-			j, err := json.MarshalIndent(hash, "", "  ")
-			if err != nil {
-				cmd.PrintErr(err)
-				return
+	xof := cmd.Flags().Changed("length") || cmd.Flags().Changed("function-name") || cmd.Flags().Changed("customization")
+	var xofOpts hash.XOFOptions
+	if xof {
+		if keyed || seeded || derive {
+			return fmt.Errorf("--length and cSHAKE strings cannot be combined with a MAC key, --seed or --derive-key")
+		}
+		if length, _ := cmd.Flags().GetString("length"); cmd.Flags().Changed("length") {
+			if xofOpts.Length, err = hash.ParseLength(length); err != nil {
+				return err
 			}
-			cmd.Println(string(j))
-		} else {
-			cmd.Println(hash.HexDigest)
 		}
-		return
+		xofOpts.FunctionName, _ = cmd.Flags().GetString("function-name")
+		xofOpts.Customization, _ = cmd.Flags().GetString("customization")
+	}
+
+	cpOpts, checkpointed, err := checkpointOptions(cmd)
+	if err != nil {
+		return err
+	}
+	if checkpointed {
+		if !isFile {
+			return fmt.Errorf("--checkpoint needs a file (-f)")
+		}
+		if keyed || seeded || xof || derive || cmd.Flags().Changed("format") {
+			return fmt.Errorf("--checkpoint cannot be combined with keyed hashing, --seed, --length, --derive-key or --format")
+		}
+	}
+
+	rg, ranged, err := sectionRange(cmd)
+	if err != nil {
+		return err
+	}
+	quickN, quick, err := quickSize(cmd)
+	if err != nil {
+		return err
+	}
+	if ranged || quick {
+		if !isFile {
+			return fmt.Errorf("--offset, --range-length, --skip-tail and --quick need a file (-f)")
+		}
+		if checkpointed || cmd.Flags().Changed("format") {
+			return fmt.Errorf("--offset, --range-length, --skip-tail and --quick cannot be combined with --checkpoint or --format")
+		}
+	}
+	if quick {
+		if ranged || keyed || seeded || xof || derive {
+			return fmt.Errorf("--quick cannot be combined with a range, keyed hashing, --seed, --length or --derive-key")
+		}
+		if hashType == "" {
+			hashType = defaultManifestHash
+		}
+	}
+
+	// A range of a file is hashed like stdin, from a reader over the
+	// section.
+	var in io.Reader = os.Stdin
+	isReader := isStdin
+	if ranged {
+		section, err := hash.OpenSection(filePath, rg)
+		if err != nil {
+			return err
+		}
+		defer section.Close()
+		in, isReader = section, true
+	}
+
+	// A single algorithm prints just its digest; lists, families and groups
+	// go through the multi-hash path below.
+	_, lookupErr := hash.Lookup(hashType)
+	single := hashType != "" && lookupErr == nil
+	if strings.Contains(hashType, "=") && lookupErr != nil {
+		return lookupErr
+	}
+
+	if !single && (keyed || seeded || xof || quick || verify != "") {
+		return fmt.Errorf("keyed hashing, --seed, --length, --quick and --verify need a single hash type (-t)")
+	}
+
+	enc, err := outputEncoding(cmd)
+	if err != nil {
+		return err
+	}
+
+	if cmd.Flags().Changed("format") {
+		if keyed || seeded || xof || derive || verify != "" {
+			return fmt.Errorf("--format cannot be combined with keyed hashing, --seed, --length, --derive-key or --verify")
+		}
+		switch {
+		case isStdin:
+			return hashInputManifest(cmd, os.Stdin, "-", hashType)
+		case isFile:
+			return hashInputManifest(cmd, nil, filePath, hashType)
+		default:
+			return hashInputManifest(cmd, strings.NewReader(args[0]), "-", hashType)
+		}
+	}
+
+	if single {
+		var gh *hash.GenericHash
+		var err error
+		switch {
+		case checkpointed:
+			gh, err = hash.HashFileCheckpoint(cmd.Context(), filePath, hashType, cpOpts)
+		case quick:
+			gh, err = hash.FingerprintFile(cmd.Context(), filePath, hashType, quickN)
+		case derive && isReader:
+			gh, err = hash.ComputeDeriveKeyReader(cmd.Context(), in, deriveContext)
+		case derive && isFile:
+			gh, err = hash.ComputeDeriveKey(cmd.Context(), []byte(filePath), deriveContext, true)
+		case derive:
+			gh, err = hash.ComputeDeriveKey(cmd.Context(), []byte(args[0]), deriveContext, false)
+		case xof && isReader:
+			gh, err = hash.ComputeXOFReader(cmd.Context(), in, hashType, xofOpts)
+		case xof && isFile:
+			gh, err = hash.ComputeXOF(cmd.Context(), []byte(filePath), hashType, xofOpts, true)
+		case xof:
+			gh, err = hash.ComputeXOF(cmd.Context(), []byte(args[0]), hashType, xofOpts, false)
+		case seeded && isReader:
+			gh, err = hash.ComputeHashSeedReader(cmd.Context(), in, hashType, seed)
+		case seeded && isFile:
+			gh, err = hash.ComputeHashSeed(cmd.Context(), []byte(filePath), hashType, seed, true)
+		case seeded:
+			gh, err = hash.ComputeHashSeed(cmd.Context(), []byte(args[0]), hashType, seed, false)
+		case keyed && isReader:
+			gh, err = hash.ComputeHMACReader(cmd.Context(), in, hashType, key)
+		case keyed && isFile:
+			gh, err = hash.ComputeHMAC(cmd.Context(), []byte(filePath), hashType, key, true)
+		case keyed:
+			gh, err = hash.ComputeHMAC(cmd.Context(), []byte(args[0]), hashType, key, false)
+		case isReader:
+			gh, err = hash.ComputeHashReader(cmd.Context(), in, hashType)
+		case isFile:
+			gh, err = hash.ComputeHash(cmd.Context(), []byte(filePath), hashType, true)
+		default:
+			gh, err = hash.ComputeHash(cmd.Context(), []byte(args[0]), hashType, false)
+		}
+		if err != nil {
+			return err
+		}
+
+		if verify != "" {
+			warnBroken(cmd, gh.Algorithm, keyed)
+			if !gh.Verify(verify) {
+				cmd.Println("FAILED")
+				return exitCode(1)
+			}
+			cmd.Println("OK")
+			return nil
+		}
+
+		if enc != nil {
+			if err := gh.Encode(gh.Algorithm, enc); err != nil {
+				return err
+			}
+		}
+
+		printHash(cmd, gh, jsonOutput)
+		return nil
+	}
+
+	var algos []string
+	if hashType != "" {
+		algos = []string{hashType}
 	}
 
 	var hashes hash.Hashes
-	var err error
-	if isFile {
-		hashes, err = hash.HasherMultiFile(string(data))
-	} else {
-		hashes, err = hash.HasherMulti(data)
+	switch {
+	case checkpointed:
+		hashes, err = hash.HasherMultiFileCheckpoint(cmd.Context(), filePath, cpOpts, algos...)
+	case isReader:
+		hashes, err = hash.HashReader(cmd.Context(), in, algos...)
+	case isFile:
+		hashes, err = hash.HasherMultiFile(cmd.Context(), filePath, algos...)
+	default:
+		hashes, err = hash.HasherMulti(cmd.Context(), []byte(args[0]), algos...)
 	}
 	if err != nil {
-		cmd.PrintErr(err)
+		return err
+	}
+
+	if enc != nil {
+		if err := hashes.Encode(enc); err != nil {
+			return err
+		}
+	}
+
+	printHashes(cmd, hashes, jsonOutput)
+	return nil
+}
+
+func printHash(cmd *cobra.Command, gh *hash.GenericHash, jsonOutput bool) {
+	if jsonOutput {
+		j, err := json.MarshalIndent(gh, "", "  ")
+		if err != nil {
+			cmd.PrintErr(err)
+			return
+		}
+		cmd.Println(string(j))
+		return
+	}
+
+	if gh.Digest != "" {
+		cmd.Println(gh.Digest)
+		return
+	}
+
+	cmd.Println(gh.HexDigest)
+}
+
+// warnBroken warns on stderr when a digest of a broken algorithm is
+// verified. Keyed modes such as HMAC do not rely on collision resistance
+// and are not warned about.
+func warnBroken(cmd *cobra.Command, name string, keyed bool) {
+	algo, err := hash.Lookup(name)
+	if err != nil || keyed || algo.Security != hash.SecurityBroken {
 		return
 	}
 
+	cmd.PrintErrf("hashit: WARNING: %s is broken (%s); a matching digest does not prove the data is unmodified\n", algo.Name, algo.SecurityNote)
+}
+
+// outputEncoding returns the encoder selected by --encoding, or nil when the
+// flag was not given and digests are printed in hex.
+func outputEncoding(cmd *cobra.Command) (hash.Encoder, error) {
+	if !cmd.Flags().Changed("encoding") {
+		return nil, nil
+	}
+
+	name, _ := cmd.Flags().GetString("encoding")
+	return hash.ParseEncoding(name)
+}
+
+func printHashes(cmd *cobra.Command, hashes hash.Hashes, jsonOutput bool) {
 	if jsonOutput {
 		j, err := json.MarshalIndent(hashes, "", "  ")
 		if err != nil {
 			cmd.PrintErr(err)
 			return
 		}
-
 		cmd.Println(string(j))
 		return
-	} else {
-		for _, h := range hashes.Array() {
-			cmd.Printf("%s: %s\n", h.Type, h.Hash)
-		}
 	}
 
+	for _, h := range hashes.Array() {
+		cmd.Printf("%s: %s\n", h.Type, h.Hash)
+	}
 }
 
+// stdinIsPipe reports whether stdin is redirected from a pipe or file rather
+// than attached to a terminal.
+func stdinIsPipe() bool {
+	stat, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+
+	return stat.Mode()&os.ModeCharDevice == 0
+}
+
+// exitCode is returned by commands that have already reported their
+// failure and only need hashit to exit with the given status.
+type exitCode int
+
+func (c exitCode) Error() string {
+	return fmt.Sprintf("exit status %d", int(c))
+}
+
+// Execute runs the command line. SIGINT and SIGTERM cancel the context of
+// the running command: hashing stops, the progress made so far is reported
+// and hashit exits with 128 plus the signal number, or 124 after --timeout.
+// Commands that cannot check the context are wrapped with interruptible.
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Println(err)
+	rootCmd.SetOut(os.Stdout)
+
+	ctx, stop := notifyContext(context.Background())
+	defer stop()
+
+	if err := rootCmd.ExecuteContext(ctx); err != nil {
+		var code exitCode
+		if errors.As(err, &code) {
+			os.Exit(int(code))
+		}
+
+		if status, ok := canceledStatus(err); ok {
+			fmt.Fprintln(os.Stderr, "hashit:", err)
+			os.Exit(status)
+		}
+
+		fmt.Fprintln(os.Stderr, "Error:", err)
 		os.Exit(1)
 	}
 }
 
 func init() {
-	rootCmd.Flags().StringP("file", "f", "", "File to hash")
-	rootCmd.Flags().StringP("type", "t", "", "Type of hash function to use")
+	rootCmd.PersistentFlags().Duration("timeout", 0, "Stop hashing after this long, e.g. 30s or 10m (exit status 124)")
+	rootCmd.Flags().StringP("file", "f", "", "File to hash, or - for stdin")
+	rootCmd.Flags().StringP("type", "t", "", "Hash function, comma separated list, family (e.g. sha2) or group (fast, all)")
 	rootCmd.Flags().BoolP("json", "j", false, "Output as JSON")
+	rootCmd.Flags().String("crc-params", "", "Custom CRC in reveng notation, e.g. \"width=16 poly=0x1021 init=0xffff refin=false refout=false xorout=0x0000\"")
+	rootCmd.Flags().String("seed", "0", "Seed for seeded hash functions such as xxh64 (decimal or 0x hex)")
+	rootCmd.Flags().String("length", "", "Output length of extendable-output functions (shake128, shake256, blake3) in bytes, or bits with a bits suffix")
+	rootCmd.Flags().String("function-name", "", "cSHAKE function name string (N)")
+	rootCmd.Flags().String("customization", "", "cSHAKE customization string (S)")
+	rootCmd.Flags().Bool("progress", false, "Show the progress of hashing a file or stdin on stderr: a bar on a terminal, JSON lines otherwise")
+	rootCmd.Flags().StringP("encoding", "e", "hex", "Digest encoding: "+strings.Join(hash.Encodings(), ", "))
 }
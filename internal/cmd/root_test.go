@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+// runHashit runs the root command with args and returns its output and
+// error, which Execute turns into a non-zero exit status.
+func runHashit(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+
+	resetFlags()
+	t.Cleanup(resetFlags)
+
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	rootCmd.SetErr(&out)
+	rootCmd.SetArgs(args)
+	t.Cleanup(func() {
+		rootCmd.SetOut(nil)
+		rootCmd.SetErr(nil)
+		rootCmd.SetArgs(nil)
+	})
+
+	err := rootCmd.ExecuteContext(context.Background())
+	return out.String(), err
+}
+
+// resetFlags restores the defaults of the root command flags, which keep
+// their values between runs.
+func resetFlags() {
+	rootCmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if s, ok := f.Value.(pflag.SliceValue); ok {
+			s.Replace(nil)
+		} else {
+			f.Value.Set(f.DefValue)
+		}
+		f.Changed = false
+	})
+}
+
+func TestHashFailureStatus(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "data")
+	if err := os.WriteFile(filePath, []byte("Hello, World!"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "missing file with verify", args: []string{"-f", filepath.Join(t.TempDir(), "missing"), "-t", "sha256", "--verify", "abcd"}},
+		{name: "missing file", args: []string{"-f", filepath.Join(t.TempDir(), "missing"), "-t", "sha256"}},
+		{name: "bad key", args: []string{"Hello, World!", "--hmac-key", "short", "-t", "blake3"}},
+		{name: "bad key with verify", args: []string{"-f", filePath, "--hmac-key", "short", "-t", "blake3", "--verify", "abcd"}},
+		{name: "unknown hash type", args: []string{"Hello, World!", "-t", "nope"}},
+		{name: "mismatch", args: []string{"-f", filePath, "-t", "sha256", "--verify", "abcd"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if out, err := runHashit(t, test.args...); err == nil {
+				t.Errorf("Expected a non-zero exit status, got success with output %q", out)
+			}
+		})
+	}
+
+	out, err := runHashit(t, "-f", filePath, "-t", "sha256", "--verify", "dffd6021bb2bd5b0af676290809ec3a53191dd81c7f70a4b28688a362182986f")
+	if err != nil || out != "OK\n" {
+		t.Errorf("Expected OK, got %q, %v", out, err)
+	}
+}
+
+func TestNativeFormatRejectsEncoding(t *testing.T) {
+	for _, args := range [][]string{
+		{"Hello, World!", "-t", "sha256", "--format", "native", "--encoding", "base64"},
+		{"-r", t.TempDir(), "-t", "cksum", "--format", "native", "-e", "hex"},
+	} {
+		if out, err := runHashit(t, args...); err == nil {
+			t.Errorf("%v: expected an error, got output %q", args, out)
+		}
+	}
+
+	out, err := runHashit(t, "Hello, World!", "-t", "sha256", "--format", "native")
+	if err != nil || out != "dffd6021bb2bd5b0af676290809ec3a53191dd81c7f70a4b28688a362182986f  -\n" {
+		t.Errorf("Expected a hex native line, got %q, %v", out, err)
+	}
+}
+
+func TestRecursiveRejectsSingleInputFlags(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "data"), []byte("Hello, World!"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, flags := range [][]string{
+		{"-f", filepath.Join(dir, "data")},
+		{"-j"},
+		{"--progress"},
+		{"--hmac-key", "foo"},
+		{"--hmac-key-file", filepath.Join(dir, "data")},
+		{"--hmac-key-env", "HOME"},
+		{"--verify", "deadbeef"},
+		{"--derive-key", "ctx"},
+		{"--seed", "1"},
+		{"--length", "64"},
+		{"--function-name", "N"},
+		{"--customization", "S"},
+		{"--crc-params", "crc-16/modbus"},
+		{"--checkpoint", filepath.Join(dir, "cp")},
+		{"--resume"},
+		{"--checkpoint-interval", "1s"},
+		{"--offset", "1"},
+		{"--range-length", "1"},
+		{"--skip-tail", "1"},
+		{"--quick"},
+	} {
+		args := append([]string{"-r", dir, "-t", "sha256"}, flags...)
+		if out, err := runHashit(t, args...); err == nil {
+			t.Errorf("%v: expected an error, got output %q", flags, out)
+		}
+	}
+
+	out, err := runHashit(t, "-r", dir, "-t", "sha256")
+	if err != nil || out != "dffd6021bb2bd5b0af676290809ec3a53191dd81c7f70a4b28688a362182986f  "+filepath.Join(dir, "data")+"\n" {
+		t.Errorf("Expected a manifest line, got %q, %v", out, err)
+	}
+}
+
+func TestCheckWarnsBroken(t *testing.T) {
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "data")
+	if err := os.WriteFile(filePath, []byte("Hello, World!"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	sums := "65a8e27d8879283831b664bd8b7f0ad4  " + filePath + "\n" +
+		"65a8e27d8879283831b664bd8b7f0ad4  " + filePath + "\n" +
+		"dffd6021bb2bd5b0af676290809ec3a53191dd81c7f70a4b28688a362182986f  " + filePath + "\n"
+	sumsPath := filepath.Join(dir, "SUMS")
+	if err := os.WriteFile(sumsPath, []byte(sums), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := runHashit(t, "check", sumsPath)
+	if err != nil {
+		t.Fatalf("Expected success, got %v with output %q", err, out)
+	}
+	if n := strings.Count(out, "WARNING: md5 is broken"); n != 1 {
+		t.Errorf("Expected one md5 warning, got %d in %q", n, out)
+	}
+	if strings.Contains(out, "sha256 is broken") {
+		t.Errorf("Unexpected sha256 warning in %q", out)
+	}
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/TechMDW/hashit/pkg/hash"
+	"github.com/spf13/cobra"
+)
+
+// sectionRange returns the range given by --offset, --range-length and
+// --skip-tail, and whether a range was requested at all.
+func sectionRange(cmd *cobra.Command) (hash.Range, bool, error) {
+	var rg hash.Range
+	ranged := false
+
+	for _, f := range []struct {
+		name  string
+		value *int64
+	}{
+		{"offset", &rg.Offset},
+		{"range-length", &rg.Length},
+		{"skip-tail", &rg.SkipTail},
+	} {
+		if !cmd.Flags().Changed(f.name) {
+			continue
+		}
+		s, _ := cmd.Flags().GetString(f.name)
+		n, err := hash.ParseSize(s)
+		if err != nil {
+			return rg, false, fmt.Errorf("invalid --%s: %w", f.name, err)
+		}
+		*f.value = n
+		ranged = true
+	}
+
+	if cmd.Flags().Changed("range-length") && rg.Length == 0 {
+		return rg, false, fmt.Errorf("invalid --range-length: must be at least 1 byte")
+	}
+
+	return rg, ranged, nil
+}
+
+// quickSize returns the head and tail size given by --quick, and whether a
+// quick fingerprint was requested at all.
+func quickSize(cmd *cobra.Command) (int64, bool, error) {
+	if !cmd.Flags().Changed("quick") {
+		return 0, false, nil
+	}
+
+	s, _ := cmd.Flags().GetString("quick")
+	n, err := hash.ParseSize(s)
+	if err != nil || n == 0 {
+		return 0, false, fmt.Errorf("invalid --quick size %q", s)
+	}
+
+	return n, true, nil
+}
+
+func init() {
+	rootCmd.Flags().String("offset", "", "Hash -f from this byte on, e.g. 512 or 1M")
+	rootCmd.Flags().String("range-length", "", "Hash only this many bytes of -f (--length sets the output length of extendable-output functions)")
+	rootCmd.Flags().String("skip-tail", "", "Leave out this many bytes at the end of -f, e.g. a trailing signature")
+	rootCmd.Flags().String("quick", "", "Quick fingerprint of -f for change detection: hash of its first and last SIZE bytes and its size")
+	rootCmd.Flags().Lookup("quick").NoOptDefVal = hash.FormatSize(hash.DefaultFingerprintSize)
+}
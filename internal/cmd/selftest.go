@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"encoding/json"
+
+	"github.com/TechMDW/hashit/pkg/hash"
+	"github.com/spf13/cobra"
+)
+
+var selftestCmd = &cobra.Command{
+	Use:   "selftest",
+	Short: "Run the known-answer tests of every algorithm",
+	Long: `Run embedded known-answer tests for every registered algorithm and every CRC
+of the catalogue, and check that each gives the same digest when its input is
+written in pieces. The exit status is non-zero if any test fails.`,
+	Example:       "  hashit selftest\n  hashit selftest --quiet\n  hashit selftest --json",
+	RunE:          selftestRun,
+	Args:          cobra.NoArgs,
+	SilenceErrors: true,
+	SilenceUsage:  true,
+}
+
+func selftestRun(cmd *cobra.Command, args []string) error {
+	quiet, _ := cmd.Flags().GetBool("quiet")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	// A canceled run still reports the tests that completed.
+	results, runErr := hash.SelfTest(cmd.Context())
+
+	failed := 0
+	for _, res := range results {
+		if !res.Passed {
+			failed++
+		}
+	}
+
+	if jsonOutput {
+		j, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return err
+		}
+		cmd.Println(string(j))
+	} else {
+		for _, res := range results {
+			switch {
+			case !res.Passed:
+				cmd.Printf("%s: FAILED: %s\n", res.Algorithm, res.Error)
+			case quiet:
+			case res.Skipped:
+				cmd.Printf("%s: OK (no known answer)\n", res.Algorithm)
+			default:
+				cmd.Printf("%s: OK\n", res.Algorithm)
+			}
+		}
+		cmd.Printf("%d passed, %d failed\n", len(results)-failed, failed)
+	}
+
+	if runErr != nil {
+		return runErr
+	}
+	if failed > 0 {
+		return exitCode(1)
+	}
+
+	return nil
+}
+
+func init() {
+	selftestCmd.Flags().Bool("quiet", false, "Only print failed tests")
+	selftestCmd.Flags().BoolP("json", "j", false, "Output the results as JSON")
+	rootCmd.AddCommand(selftestCmd)
+}
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/TechMDW/hashit/pkg/hash"
+	"github.com/spf13/cobra"
+)
+
+// timeoutStatus is the exit status when --timeout stops hashit, as with
+// coreutils timeout.
+const timeoutStatus = 124
+
+// signalError is the cancellation cause of a command stopped by SIGINT or
+// SIGTERM.
+type signalError struct {
+	sig os.Signal
+}
+
+func (e signalError) Error() string {
+	return fmt.Sprintf("signal: %s", e.sig)
+}
+
+func (e signalError) Unwrap() error {
+	return context.Canceled
+}
+
+// status returns 128 plus the signal number, as shells report processes
+// killed by a signal.
+func (e signalError) status() int {
+	if s, ok := e.sig.(syscall.Signal); ok {
+		return 128 + int(s)
+	}
+	return 128 + int(syscall.SIGINT)
+}
+
+// timeoutError is the cancellation cause of a command stopped by --timeout.
+type timeoutError time.Duration
+
+func (e timeoutError) Error() string {
+	return fmt.Sprintf("timeout of %s exceeded", time.Duration(e))
+}
+
+func (e timeoutError) Unwrap() error {
+	return context.DeadlineExceeded
+}
+
+// notifyContext returns a context that is canceled with a signalError on
+// SIGINT or SIGTERM. The default handling is restored after the first
+// signal, so a second one terminates hashit at once, e.g. while it is
+// blocked reading stdin.
+func notifyContext(parent context.Context) (context.Context, func()) {
+	ctx, cancel := context.WithCancelCause(parent)
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case sig := <-sigs:
+			signal.Stop(sigs)
+			cancel(signalError{sig})
+		case <-ctx.Done():
+		}
+	}()
+
+	return ctx, func() {
+		signal.Stop(sigs)
+		cancel(nil)
+	}
+}
+
+// interruptible wraps the run function of a command whose work does not
+// check its context, such as a password KDF or a read from a terminal, so
+// that a signal or --timeout still stops it with the status documented in
+// Execute. run is started in a goroutine and abandoned once the context is
+// done.
+func interruptible(run func(cmd *cobra.Command, args []string) error) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		done := make(chan error, 1)
+		go func() {
+			done <- run(cmd, args)
+		}()
+
+		select {
+		case err := <-done:
+			return err
+		case <-ctx.Done():
+			return context.Cause(ctx)
+		}
+	}
+}
+
+// applyTimeout limits the context of cmd to --timeout, if given.
+func applyTimeout(cmd *cobra.Command, args []string) error {
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	if timeout < 0 {
+		return fmt.Errorf("invalid timeout %s", timeout)
+	}
+	if timeout == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeoutCause(cmd.Context(), timeout, timeoutError(timeout))
+	cmd.SetContext(ctx)
+	cobra.OnFinalize(cancel)
+	return nil
+}
+
+// isCanceled reports whether err means hashing was stopped by a signal or
+// --timeout rather than failed.
+func isCanceled(err error) bool {
+	var canceled *hash.CanceledError
+	return errors.As(err, &canceled)
+}
+
+// canceledStatus returns the exit status for an error caused by a signal or
+// --timeout.
+func canceledStatus(err error) (int, bool) {
+	var sig signalError
+	switch {
+	case errors.As(err, &sig):
+		return sig.status(), true
+	case errors.Is(err, context.DeadlineExceeded):
+		return timeoutStatus, true
+	case errors.Is(err, context.Canceled):
+		return 128 + int(syscall.SIGINT), true
+	}
+	return 0, false
+}
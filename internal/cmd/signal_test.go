@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func TestInterruptible(t *testing.T) {
+	// run blocks like a password KDF, without checking the context.
+	block := make(chan struct{})
+	defer close(block)
+	run := interruptible(func(*cobra.Command, []string) error {
+		<-block
+		return nil
+	})
+
+	timeout := 10 * time.Millisecond
+	ctx, cancel := context.WithTimeoutCause(context.Background(), timeout, timeoutError(timeout))
+	defer cancel()
+	cmd := &cobra.Command{}
+	cmd.SetContext(ctx)
+
+	err := run(cmd, nil)
+	if status, ok := canceledStatus(err); !ok || status != timeoutStatus {
+		t.Errorf("Expected exit status %d, got %v", timeoutStatus, err)
+	}
+
+	cmd.SetContext(context.Background())
+	if err := interruptible(func(*cobra.Command, []string) error { return exitCode(1) })(cmd, nil); err != exitCode(1) {
+		t.Errorf("Expected the error of run, got %v", err)
+	}
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"encoding/json"
+
+	"github.com/TechMDW/hashit/pkg/hash"
+	"github.com/spf13/cobra"
+)
+
+var treeCmd = &cobra.Command{
+	Use:   "tree DIR",
+	Short: "Compute a single digest for a whole directory",
+	Long: `Compute a single digest for a directory from the contents and relative paths of
+its files, independent of walk order and platform path separators.
+
+Formats:
+  hashit   canonical hashit encoding: the digest of "<hex>  <path>\n" lines
+           sorted by path, optionally with file modes (--modes)
+  dirhash  the Go module "h1:" digest recorded in go.sum (always sha256)
+  git      the Git tree object ID, as printed by git write-tree (sha1 by default);
+           .git directories are always skipped`,
+	Example: "  hashit tree dist\n  hashit tree --modes -t sha512 dist\n  hashit tree --format git .\n  hashit tree --format dirhash --prefix example.com/mod@v1.0.0 .",
+	RunE:    treeRun,
+	Args:    cobra.ExactArgs(1),
+}
+
+func treeRun(cmd *cobra.Command, args []string) error {
+	formatName, _ := cmd.Flags().GetString("format")
+	symlinks, _ := cmd.Flags().GetString("symlinks")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	var opts hash.TreeOptions
+	opts.Algorithm, _ = cmd.Flags().GetString("type")
+	opts.Modes, _ = cmd.Flags().GetBool("modes")
+	opts.Prefix, _ = cmd.Flags().GetString("prefix")
+	opts.Workers, _ = cmd.Flags().GetInt("workers")
+	opts.Walk.Include, _ = cmd.Flags().GetStringArray("include")
+	opts.Walk.Exclude, _ = cmd.Flags().GetStringArray("exclude")
+	opts.Walk.GitIgnore, _ = cmd.Flags().GetBool("gitignore")
+
+	var err error
+	opts.Format, err = hash.ParseTreeFormat(formatName)
+	if err != nil {
+		return err
+	}
+
+	// Git records symbolic links by their target and dirhash follows them,
+	// so default to that.
+	if symlinks == "" {
+		switch opts.Format {
+		case hash.TreeGit:
+			symlinks = "target"
+		case hash.TreeDirhash:
+			symlinks = "follow"
+		default:
+			symlinks = "skip"
+		}
+	}
+	opts.Walk.Symlinks, err = hash.ParseSymlinkPolicy(symlinks)
+	if err != nil {
+		return err
+	}
+
+	th, err := hash.HashTree(cmd.Context(), args[0], opts)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		j, err := json.MarshalIndent(th, "", "  ")
+		if err != nil {
+			return err
+		}
+		cmd.Println(string(j))
+		return nil
+	}
+
+	cmd.Println(th.ID)
+	return nil
+}
+
+func init() {
+	treeCmd.Flags().String("format", "hashit", "Tree encoding: hashit, dirhash or git")
+	treeCmd.Flags().StringP("type", "t", "", "Type of hash function to use (default sha256, sha1 for git)")
+	treeCmd.Flags().Bool("modes", false, "Include file modes in the hashit encoding")
+	treeCmd.Flags().String("prefix", "", "Path prefix for the dirhash format, e.g. module@version")
+	treeCmd.Flags().StringArray("include", nil, "Only include files matching this glob (repeatable)")
+	treeCmd.Flags().StringArray("exclude", nil, "Skip files and directories matching this glob (repeatable)")
+	treeCmd.Flags().Bool("gitignore", false, "Skip files ignored by .gitignore and .git directories")
+	treeCmd.Flags().String("symlinks", "", "Symbolic links: skip, follow or target (default skip, target for git, follow for dirhash)")
+	treeCmd.Flags().Int("workers", 0, "Number of files hashed in parallel (default one per CPU)")
+	treeCmd.Flags().BoolP("json", "j", false, "Output as JSON")
+	rootCmd.AddCommand(treeCmd)
+}
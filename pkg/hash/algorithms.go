@@ -0,0 +1,429 @@
+package hash
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"hash"
+	"hash/adler32"
+	"hash/crc32"
+	"hash/crc64"
+	"hash/fnv"
+
+	"github.com/TechMDW/hashit/pkg/hash/blake3"
+	"github.com/TechMDW/hashit/pkg/hash/sm3"
+	"github.com/TechMDW/hashit/pkg/hash/streebog"
+	"github.com/TechMDW/hashit/pkg/hash/tiger"
+	"github.com/jzelinskie/whirlpool"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/blake2s"
+	"golang.org/x/crypto/md4"
+	"golang.org/x/crypto/ripemd160"
+	"golang.org/x/crypto/sha3"
+)
+
+var (
+	crc32KoopmanTable    = crc32.MakeTable(crc32.Koopman)
+	crc32CastagnoliTable = crc32.MakeTable(crc32.Castagnoli)
+	crc64ISOTable        = crc64.MakeTable(crc64.ISO)
+	crc64ECMATable       = crc64.MakeTable(crc64.ECMA)
+)
+
+// builtins lists the algorithms shipped with hashit in the order they are
+// reported by ComputeHashList and Hashes.Array.
+var builtins = []*Algorithm{
+	{
+		Name:    "adler32",
+		Aliases: []string{"adler-32"},
+		Family:  "adler",
+		New:     func() hash.Hash { return adler32.New() },
+		field:   func(h *Hashes) *string { return &h.Adler32 },
+	},
+	{
+		Name:     "md4",
+		Family:   "md",
+		New:      md4.New,
+		NewKeyed: hmacKeyed(md4.New),
+		field:    func(h *Hashes) *string { return &h.MD4 },
+	},
+	{
+		Name:     "md5",
+		Family:   "md",
+		New:      md5.New,
+		NewKeyed: hmacKeyed(md5.New),
+		field:    func(h *Hashes) *string { return &h.MD5 },
+	},
+	{
+		Name:     "sha1",
+		Aliases:  []string{"sha-1"},
+		Family:   "sha1",
+		New:      sha1.New,
+		NewKeyed: hmacKeyed(sha1.New),
+		field:    func(h *Hashes) *string { return &h.SHA1 },
+	},
+	{
+		Name:     "sha224",
+		Aliases:  []string{"sha-224"},
+		Family:   "sha2",
+		New:      sha256.New224,
+		NewKeyed: hmacKeyed(sha256.New224),
+		field:    func(h *Hashes) *string { return &h.SHA2.SHA224 },
+	},
+	{
+		Name:     "sha256",
+		Aliases:  []string{"sha-256"},
+		Family:   "sha2",
+		New:      sha256.New,
+		NewKeyed: hmacKeyed(sha256.New),
+		field:    func(h *Hashes) *string { return &h.SHA2.SHA256 },
+	},
+	{
+		Name:     "sha384",
+		Aliases:  []string{"sha-384"},
+		Family:   "sha2",
+		New:      sha512.New384,
+		NewKeyed: hmacKeyed(sha512.New384),
+		field:    func(h *Hashes) *string { return &h.SHA2.SHA384 },
+	},
+	{
+		Name:     "sha512",
+		Aliases:  []string{"sha-512"},
+		Family:   "sha2",
+		New:      sha512.New,
+		NewKeyed: hmacKeyed(sha512.New),
+		field:    func(h *Hashes) *string { return &h.SHA2.SHA512 },
+	},
+	{
+		Name:     "sha512_224",
+		Aliases:  []string{"sha-512/224", "sha512/224", "sha512t224"},
+		Family:   "sha2",
+		New:      sha512.New512_224,
+		NewKeyed: hmacKeyed(sha512.New512_224),
+		field:    func(h *Hashes) *string { return &h.SHA2.SHA512_224 },
+	},
+	{
+		Name:     "sha512_256",
+		Aliases:  []string{"sha-512/256", "sha512/256", "sha512t256"},
+		Family:   "sha2",
+		New:      sha512.New512_256,
+		NewKeyed: hmacKeyed(sha512.New512_256),
+		field:    func(h *Hashes) *string { return &h.SHA2.SHA512_256 },
+	},
+	{
+		Name:     "sha3_224",
+		Aliases:  []string{"sha3-224"},
+		Family:   "sha3",
+		New:      sha3.New224,
+		NewKeyed: kmacKeyed(128, 28),
+		field:    func(h *Hashes) *string { return &h.SHA3.SHA224 },
+	},
+	{
+		Name:     "sha3_256",
+		Aliases:  []string{"sha3-256"},
+		Family:   "sha3",
+		New:      sha3.New256,
+		NewKeyed: kmacKeyed(128, 32),
+		field:    func(h *Hashes) *string { return &h.SHA3.SHA256 },
+	},
+	{
+		Name:     "sha3_384",
+		Aliases:  []string{"sha3-384"},
+		Family:   "sha3",
+		New:      sha3.New384,
+		NewKeyed: kmacKeyed(256, 48),
+		field:    func(h *Hashes) *string { return &h.SHA3.SHA384 },
+	},
+	{
+		Name:     "sha3_512",
+		Aliases:  []string{"sha3-512"},
+		Family:   "sha3",
+		New:      sha3.New512,
+		NewKeyed: kmacKeyed(256, 64),
+		field:    func(h *Hashes) *string { return &h.SHA3.SHA512 },
+	},
+	{
+		Name:     "shake128",
+		Aliases:  []string{"cshake128"},
+		Family:   "sha3",
+		New:      func() hash.Hash { return sha3.NewShake128() },
+		NewKeyed: kmacKeyed(128, 32),
+		NewXOF:   shakeXOF(128),
+		field:    func(h *Hashes) *string { return &h.SHA3.Shake128 },
+	},
+	{
+		Name:     "shake256",
+		Aliases:  []string{"cshake256"},
+		Family:   "sha3",
+		New:      func() hash.Hash { return sha3.NewShake256() },
+		NewKeyed: kmacKeyed(256, 64),
+		NewXOF:   shakeXOF(256),
+		field:    func(h *Hashes) *string { return &h.SHA3.Shake256 },
+	},
+	{
+		Name:     "keccak256",
+		Aliases:  []string{"keccak-256"},
+		Family:   "sha3",
+		New:      sha3.NewLegacyKeccak256,
+		NewKeyed: hmacKeyed(sha3.NewLegacyKeccak256),
+		field:    func(h *Hashes) *string { return &h.SHA3.Keccak256 },
+	},
+	{
+		Name:     "keccak512",
+		Aliases:  []string{"keccak-512"},
+		Family:   "sha3",
+		New:      sha3.NewLegacyKeccak512,
+		NewKeyed: hmacKeyed(sha3.NewLegacyKeccak512),
+		field:    func(h *Hashes) *string { return &h.SHA3.Keccak512 },
+	},
+	{
+		Name:     "ripemd160",
+		Aliases:  []string{"ripemd-160", "rmd160"},
+		Family:   "ripemd",
+		New:      ripemd160.New,
+		NewKeyed: hmacKeyed(ripemd160.New),
+		field:    func(h *Hashes) *string { return &h.RIPEMD.RIPEMD160 },
+	},
+	{
+		Name:     "hash160",
+		Family:   "ripemd",
+		New:      NewHash160,
+		NewKeyed: hmacKeyed(NewHash160),
+		field:    func(h *Hashes) *string { return &h.RIPEMD.Hash160 },
+	},
+	{
+		Name:     "whirlpool",
+		Family:   "whirlpool",
+		New:      whirlpool.New,
+		NewKeyed: hmacKeyed(whirlpool.New),
+		field:    func(h *Hashes) *string { return &h.Whirlpool },
+	},
+	{
+		Name:     "tiger",
+		Aliases:  []string{"tiger192", "tiger1"},
+		Family:   "tiger",
+		New:      tiger.New,
+		NewKeyed: hmacKeyed(tiger.New),
+		field:    func(h *Hashes) *string { return &h.Tiger.Tiger },
+	},
+	{
+		Name:     "tiger2",
+		Family:   "tiger",
+		New:      tiger.New2,
+		NewKeyed: hmacKeyed(tiger.New2),
+		field:    func(h *Hashes) *string { return &h.Tiger.Tiger2 },
+	},
+	{
+		Name:     "sm3",
+		Family:   "sm3",
+		New:      sm3.New,
+		NewKeyed: hmacKeyed(sm3.New),
+		field:    func(h *Hashes) *string { return &h.SM3 },
+	},
+	{
+		Name:     "streebog256",
+		Aliases:  []string{"streebog-256", "gost2012-256", "stribog256"},
+		Family:   "streebog",
+		New:      streebog.New256,
+		NewKeyed: hmacKeyed(streebog.New256),
+		field:    func(h *Hashes) *string { return &h.Streebog.Streebog256 },
+	},
+	{
+		Name:     "streebog512",
+		Aliases:  []string{"streebog-512", "gost2012-512", "stribog512"},
+		Family:   "streebog",
+		New:      streebog.New512,
+		NewKeyed: hmacKeyed(streebog.New512),
+		field:    func(h *Hashes) *string { return &h.Streebog.Streebog512 },
+	},
+	{
+		Name:   "fnv32",
+		Family: "fnv",
+		New:    func() hash.Hash { return fnv.New32() },
+		field:  func(h *Hashes) *string { return &h.FNV.FNV32 },
+	},
+	{
+		Name:   "fnv32a",
+		Family: "fnv",
+		New:    func() hash.Hash { return fnv.New32a() },
+		field:  func(h *Hashes) *string { return &h.FNV.FNV32a },
+	},
+	{
+		Name:   "fnv64",
+		Family: "fnv",
+		New:    func() hash.Hash { return fnv.New64() },
+		field:  func(h *Hashes) *string { return &h.FNV.FNV64 },
+	},
+	{
+		Name:   "fnv64a",
+		Family: "fnv",
+		New:    func() hash.Hash { return fnv.New64a() },
+		field:  func(h *Hashes) *string { return &h.FNV.FNV64a },
+	},
+	{
+		Name:    "crc32_ieee",
+		Aliases: []string{"crc32"},
+		Family:  "crc",
+		New:     func() hash.Hash { return crc32.NewIEEE() },
+		label:   "crc32_IEEE",
+		field:   func(h *Hashes) *string { return &h.CRC.CRC32IEEE },
+	},
+	{
+		Name:   "crc32_koopman",
+		Family: "crc",
+		New:    func() hash.Hash { return crc32.New(crc32KoopmanTable) },
+		label:  "crc32_Koopman",
+		field:  func(h *Hashes) *string { return &h.CRC.CRC32Koopman },
+	},
+	{
+		Name:    "crc32_castagnoli",
+		Aliases: []string{"crc32c"},
+		Family:  "crc",
+		New:     func() hash.Hash { return crc32.New(crc32CastagnoliTable) },
+		label:   "crc32_Castagnoli",
+		field:   func(h *Hashes) *string { return &h.CRC.CRC32Castagnoli },
+	},
+	{
+		Name:    "crc64_iso",
+		Aliases: []string{"crc64"},
+		Family:  "crc",
+		New:     func() hash.Hash { return crc64.New(crc64ISOTable) },
+		label:   "crc64_ISO",
+		field:   func(h *Hashes) *string { return &h.CRC.CRC64IOS },
+	},
+	{
+		Name:   "crc64_ecma",
+		Family: "crc",
+		New:    func() hash.Hash { return crc64.New(crc64ECMATable) },
+		label:  "crc64_ECMA",
+		field:  func(h *Hashes) *string { return &h.CRC.CRC64ECMA },
+	},
+	{
+		Name:     "blake2b256",
+		Aliases:  []string{"blake2b-256"},
+		Family:   "blake",
+		New:      func() hash.Hash { h, _ := blake2b.New256(nil); return h },
+		NewKeyed: func(key []byte) (hash.Hash, error) { return blake2b.New256(key) },
+		field:    func(h *Hashes) *string { return &h.Blake.Blake2b256 },
+	},
+	{
+		Name:     "blake2b384",
+		Aliases:  []string{"blake2b-384"},
+		Family:   "blake",
+		New:      func() hash.Hash { h, _ := blake2b.New384(nil); return h },
+		NewKeyed: func(key []byte) (hash.Hash, error) { return blake2b.New384(key) },
+		field:    func(h *Hashes) *string { return &h.Blake.Blake2b384 },
+	},
+	{
+		Name:     "blake2b512",
+		Aliases:  []string{"blake2b-512", "blake2b"},
+		Family:   "blake",
+		New:      func() hash.Hash { h, _ := blake2b.New512(nil); return h },
+		NewKeyed: func(key []byte) (hash.Hash, error) { return blake2b.New512(key) },
+		field:    func(h *Hashes) *string { return &h.Blake.Blake2b512 },
+	},
+	{
+		Name:     "blake2s256",
+		Aliases:  []string{"blake2s-256", "blake2s"},
+		Family:   "blake",
+		New:      func() hash.Hash { h, _ := blake2s.New256(nil); return h },
+		NewKeyed: func(key []byte) (hash.Hash, error) { return blake2s.New256(key) },
+		field:    func(h *Hashes) *string { return &h.Blake.Blake2s256 },
+	},
+	{
+		Name:     "blake3",
+		Aliases:  []string{"blake3-256", "b3"},
+		Family:   "blake",
+		New:      func() hash.Hash { return blake3.New() },
+		NewKeyed: func(key []byte) (hash.Hash, error) { return blake3.NewKeyed(key) },
+		NewXOF:   blake3XOF,
+		field:    func(h *Hashes) *string { return &h.Blake.Blake3 },
+	},
+	{
+		Name:      "xxh32",
+		Aliases:   []string{"xxhash32"},
+		Family:    "xxhash",
+		New:       func() hash.Hash { return NewXXH32(0) },
+		NewSeeded: seeded32,
+		field:     func(h *Hashes) *string { return &h.XXHash.XXH32 },
+	},
+	{
+		Name:      "xxh64",
+		Aliases:   []string{"xxhash64", "xxhash"},
+		Family:    "xxhash",
+		New:       func() hash.Hash { return NewXXH64(0) },
+		NewSeeded: func(seed uint64) (hash.Hash, error) { return NewXXH64(seed), nil },
+		field:     func(h *Hashes) *string { return &h.XXHash.XXH64 },
+	},
+	{
+		Name:      "xxh3",
+		Aliases:   []string{"xxh3_64", "xxh3-64"},
+		Family:    "xxhash",
+		New:       func() hash.Hash { return NewXXH3(0) },
+		NewSeeded: func(seed uint64) (hash.Hash, error) { return NewXXH3(seed), nil },
+		field:     func(h *Hashes) *string { return &h.XXHash.XXH3 },
+	},
+	{
+		Name:      "xxh128",
+		Aliases:   []string{"xxh3_128", "xxh3-128"},
+		Family:    "xxhash",
+		New:       func() hash.Hash { return NewXXH128(0) },
+		NewSeeded: func(seed uint64) (hash.Hash, error) { return NewXXH128(seed), nil },
+		field:     func(h *Hashes) *string { return &h.XXHash.XXH128 },
+	},
+	{
+		Name:    "cksum",
+		Aliases: []string{"posix-cksum"},
+		Family:  "checksum",
+		New:     func() hash.Hash { return NewCksum() },
+		field:   func(h *Hashes) *string { return &h.Checksum.Cksum },
+	},
+	{
+		Name:    "sum_bsd",
+		Aliases: []string{"sum-bsd", "bsdsum", "sum-r"},
+		Family:  "checksum",
+		New:     NewBSDSum,
+		field:   func(h *Hashes) *string { return &h.Checksum.BSDSum },
+	},
+	{
+		Name:    "sum_sysv",
+		Aliases: []string{"sum-sysv", "sysvsum", "sum-s"},
+		Family:  "checksum",
+		New:     NewSysVSum,
+		field:   func(h *Hashes) *string { return &h.Checksum.SysVSum },
+	},
+	{
+		Name:    "fletcher16",
+		Aliases: []string{"fletcher-16"},
+		Family:  "checksum",
+		New:     NewFletcher16,
+		field:   func(h *Hashes) *string { return &h.Checksum.Fletcher16 },
+	},
+	{
+		Name:    "fletcher32",
+		Aliases: []string{"fletcher-32"},
+		Family:  "checksum",
+		New:     NewFletcher32,
+		field:   func(h *Hashes) *string { return &h.Checksum.Fletcher32 },
+	},
+	{
+		Name:    "fletcher64",
+		Aliases: []string{"fletcher-64"},
+		Family:  "checksum",
+		New:     NewFletcher64,
+		field:   func(h *Hashes) *string { return &h.Checksum.Fletcher64 },
+	},
+	{
+		Name:    "inet_checksum",
+		Aliases: []string{"inet-checksum", "rfc1071", "internet"},
+		Family:  "checksum",
+		New:     NewInternetChecksum,
+		field:   func(h *Hashes) *string { return &h.Checksum.Internet },
+	},
+}
+
+func init() {
+	for _, a := range builtins {
+		mustRegister(a)
+	}
+}
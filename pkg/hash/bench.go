@@ -0,0 +1,190 @@
+package hash
+
+import (
+	"context"
+	"math/rand"
+	"runtime"
+	"time"
+
+	"github.com/klauspost/cpuid/v2"
+)
+
+// DefaultBenchSizes are the input sizes measured by Bench unless others are
+// given.
+var DefaultBenchSizes = []int64{64, 1 << 10, 64 << 10, 1 << 20}
+
+// BenchOptions configures Bench.
+type BenchOptions struct {
+	// Algorithms are selectors as for SelectAlgorithms. None selects every
+	// registered algorithm.
+	Algorithms []string
+	// Sizes are the input sizes in bytes, DefaultBenchSizes when empty.
+	Sizes []int64
+	// Duration is the minimum time spent on each measurement, 100ms when
+	// zero.
+	Duration time.Duration
+	// Multi adds a measurement of HasherMulti computing all selected
+	// algorithms at once for every size.
+	Multi bool
+}
+
+// BenchMulti is the algorithm name of the HasherMulti measurements.
+const BenchMulti = "multi"
+
+// BenchResult is the measurement of one algorithm at one input size. A call
+// creates a hash, writes the whole input and computes the digest.
+type BenchResult struct {
+	// Algorithm is the name of the algorithm, or BenchMulti.
+	Algorithm string `json:"algorithm"`
+	Size      int64  `json:"size"`
+	Calls     int    `json:"calls"`
+	// Latency is the mean time per call.
+	Latency time.Duration `json:"latencyNs"`
+	// MBPerSec is the throughput in MB (10^6 bytes) per second.
+	MBPerSec float64 `json:"mbPerSec"`
+}
+
+// CPUInfo describes the processor the benchmark ran on.
+type CPUInfo struct {
+	Brand string `json:"brand"`
+	Arch  string `json:"arch"`
+	// Cores is the number of logical cores.
+	Cores int `json:"cores"`
+	// Features lists the detected instruction set extensions that hash
+	// implementations use, e.g. "AVX2", "SHA-NI" or "ARM SHA2".
+	Features []string `json:"features"`
+}
+
+// cpuFeatures maps instruction set extensions to the names reported in
+// CPUInfo.Features.
+var cpuFeatures = []struct {
+	id   cpuid.FeatureID
+	name string
+}{
+	{cpuid.SSE42, "SSE4.2"},
+	{cpuid.AVX, "AVX"},
+	{cpuid.AVX2, "AVX2"},
+	{cpuid.AVX512F, "AVX-512F"},
+	{cpuid.BMI2, "BMI2"},
+	{cpuid.SHA, "SHA-NI"},
+	{cpuid.AESNI, "AES-NI"},
+	{cpuid.CLMUL, "PCLMULQDQ"},
+	{cpuid.ASIMD, "ARM NEON"},
+	{cpuid.SHA1, "ARM SHA1"},
+	{cpuid.SHA2, "ARM SHA2"},
+	{cpuid.SHA3, "ARM SHA3"},
+	{cpuid.SHA512, "ARM SHA512"},
+	{cpuid.CRC32, "ARM CRC32"},
+	{cpuid.PMULL, "ARM PMULL"},
+}
+
+// DetectCPU returns the processor brand and the instruction set extensions
+// relevant to hashing.
+func DetectCPU() CPUInfo {
+	info := CPUInfo{
+		Brand:    cpuid.CPU.BrandName,
+		Arch:     runtime.GOARCH,
+		Cores:    runtime.NumCPU(),
+		Features: []string{},
+	}
+
+	for _, f := range cpuFeatures {
+		if cpuid.CPU.Supports(f.id) {
+			info.Features = append(info.Features, f.name)
+		}
+	}
+
+	return info
+}
+
+// Bench measures the throughput and latency of the selected algorithms for
+// every input size, and of HasherMulti when opts.Multi is set. It stops with
+// a CanceledError once ctx is done, returning the measurements completed so
+// far.
+func Bench(ctx context.Context, opts BenchOptions) ([]BenchResult, error) {
+	algos, err := resolveAlgorithms(opts.Algorithms)
+	if err != nil {
+		return nil, err
+	}
+
+	sizes := opts.Sizes
+	if len(sizes) == 0 {
+		sizes = DefaultBenchSizes
+	}
+	duration := opts.Duration
+	if duration <= 0 {
+		duration = 100 * time.Millisecond
+	}
+
+	var maxSize int64
+	for _, size := range sizes {
+		maxSize = max(maxSize, size)
+	}
+	data := make([]byte, maxSize)
+	rand.New(rand.NewSource(1)).Read(data)
+
+	var results []BenchResult
+	for _, size := range sizes {
+		input := data[:size]
+
+		for _, a := range algos {
+			res, err := measure(ctx, a.Name, size, duration, func() error {
+				h := a.New()
+				h.Write(input)
+				h.Sum(nil)
+				return nil
+			})
+			if err != nil {
+				return results, err
+			}
+			results = append(results, res)
+		}
+
+		if opts.Multi {
+			res, err := measure(ctx, BenchMulti, size, duration, func() error {
+				_, err := HasherMulti(ctx, input, opts.Algorithms...)
+				return err
+			})
+			if err != nil {
+				return results, err
+			}
+			results = append(results, res)
+		}
+	}
+
+	return results, nil
+}
+
+// measure calls f repeatedly for at least duration, after one warm-up call.
+// It gives up with a CanceledError once ctx is done, and with the error of
+// f when a call fails.
+func measure(ctx context.Context, name string, size int64, duration time.Duration, f func() error) (BenchResult, error) {
+	if err := canceled(ctx, 0); err != nil {
+		return BenchResult{}, err
+	}
+	if err := f(); err != nil {
+		return BenchResult{}, err
+	}
+
+	calls := 0
+	start := time.Now()
+	elapsed := time.Duration(0)
+	for elapsed < duration {
+		if err := canceled(ctx, 0); err != nil {
+			return BenchResult{}, err
+		}
+		if err := f(); err != nil {
+			return BenchResult{}, err
+		}
+		calls++
+		elapsed = time.Since(start)
+	}
+
+	return BenchResult{
+		Algorithm: name,
+		Size:      size,
+		Calls:     calls,
+		Latency:   elapsed / time.Duration(calls),
+		MBPerSec:  float64(size) * float64(calls) / elapsed.Seconds() / 1e6,
+	}, nil
+}
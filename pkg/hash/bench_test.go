@@ -0,0 +1,61 @@
+package hash_test
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"testing"
+	"time"
+
+	. "github.com/TechMDW/hashit/pkg/hash"
+)
+
+func TestBench(t *testing.T) {
+	results, err := Bench(context.Background(), BenchOptions{
+		Algorithms: []string{"sha256,crc32"},
+		Sizes:      []int64{64, 1024},
+		Duration:   time.Millisecond,
+		Multi:      true,
+	})
+	if err != nil {
+		t.Fatalf("Bench failed: %v", err)
+	}
+
+	expected := []struct {
+		algorithm string
+		size      int64
+	}{
+		{"sha256", 64}, {"crc32_ieee", 64}, {BenchMulti, 64},
+		{"sha256", 1024}, {"crc32_ieee", 1024}, {BenchMulti, 1024},
+	}
+	if len(results) != len(expected) {
+		t.Fatalf("Expected %d results, got %d", len(expected), len(results))
+	}
+	for i, res := range results {
+		if res.Algorithm != expected[i].algorithm || res.Size != expected[i].size {
+			t.Errorf("Result %d: got %s/%d, expected %s/%d", i, res.Algorithm, res.Size, expected[i].algorithm, expected[i].size)
+		}
+		if res.Calls <= 0 || res.Latency <= 0 || res.MBPerSec <= 0 {
+			t.Errorf("Result %d: invalid measurement %+v", i, res)
+		}
+	}
+}
+
+func TestBenchCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := Bench(ctx, BenchOptions{Algorithms: []string{"md5"}}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if _, err := Bench(context.Background(), BenchOptions{Algorithms: []string{"nope"}}); err == nil {
+		t.Error("Expected error for unknown algorithm, got nil")
+	}
+}
+
+func TestDetectCPU(t *testing.T) {
+	info := DetectCPU()
+	if info.Arch != runtime.GOARCH || info.Cores != runtime.NumCPU() || info.Features == nil {
+		t.Errorf("Unexpected CPU info %+v", info)
+	}
+}
@@ -0,0 +1,21 @@
+package hash
+
+import (
+	"context"
+	"io"
+
+	"github.com/TechMDW/hashit/pkg/hash/blake3"
+)
+
+// ComputeDeriveKey returns 32 bytes of key material derived from data with
+// the BLAKE3 derive-key mode. keyContext should be a hardcoded, globally
+// unique and application specific string.
+func ComputeDeriveKey(ctx context.Context, data []byte, keyContext string, file bool) (*GenericHash, error) {
+	return compute(ctx, data, "blake3", blake3.NewDeriveKey(keyContext), file)
+}
+
+// ComputeDeriveKeyReader returns 32 bytes of key material derived from
+// everything read from r with the BLAKE3 derive-key mode.
+func ComputeDeriveKeyReader(ctx context.Context, r io.Reader, keyContext string) (*GenericHash, error) {
+	return hashReader(ctx, r, "blake3", blake3.NewDeriveKey(keyContext))
+}
@@ -0,0 +1,430 @@
+// Package blake3 implements the BLAKE3 hash function with its keyed and
+// derive-key modes and extendable output.
+//
+// Large writes are split into complete subtrees whose chunks are hashed on
+// all available CPUs, so hashing a big file with a single Hasher scales with
+// the number of cores.
+package blake3
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash"
+	"io"
+	"math/bits"
+	"runtime"
+	"sync"
+)
+
+const (
+	// Size is the default output size in bytes.
+	Size = 32
+	// KeySize is the size of a key for keyed hashing.
+	KeySize = 32
+	// BlockSize is the compression block size in bytes.
+	BlockSize = 64
+
+	chunkLen = 1024
+
+	flagChunkStart        = 1 << 0
+	flagChunkEnd          = 1 << 1
+	flagParent            = 1 << 2
+	flagRoot              = 1 << 3
+	flagKeyedHash         = 1 << 4
+	flagDeriveKeyContext  = 1 << 5
+	flagDeriveKeyMaterial = 1 << 6
+
+	// parallelChunks is the smallest subtree, in chunks, that is split
+	// across goroutines.
+	parallelChunks = 64
+)
+
+var iv = [8]uint32{
+	0x6A09E667, 0xBB67AE85, 0x3C6EF372, 0xA54FF53A,
+	0x510E527F, 0x9B05688C, 0x1F83D9AB, 0x5BE0CD19,
+}
+
+// ErrKeySize is returned by NewKeyed for keys that are not KeySize bytes.
+var ErrKeySize = errors.New("blake3: key must be 32 bytes")
+
+// Hasher is an incremental BLAKE3 hash. It implements hash.Hash; Sum returns
+// Size() bytes of output and XOF any number of bytes.
+type Hasher struct {
+	key   [8]uint32
+	flags uint32
+	size  int
+
+	chunk   chunkState
+	cvStack [][8]uint32
+}
+
+var _ hash.Hash = (*Hasher)(nil)
+
+// New returns a Hasher computing the default 32-byte BLAKE3 hash.
+func New() *Hasher {
+	return newHasher(iv, 0, Size)
+}
+
+// NewSize returns a Hasher whose Sum returns size bytes of output.
+func NewSize(size int) *Hasher {
+	return newHasher(iv, 0, size)
+}
+
+// NewKeyed returns a Hasher computing the keyed hash (a MAC) with a 32-byte
+// key.
+func NewKeyed(key []byte) (*Hasher, error) {
+	if len(key) != KeySize {
+		return nil, ErrKeySize
+	}
+
+	return newHasher(keyWords(key), flagKeyedHash, Size), nil
+}
+
+// NewDeriveKey returns a Hasher deriving key material from its input in the
+// given context. The context should be a hardcoded, globally unique and
+// application specific string.
+func NewDeriveKey(context string) *Hasher {
+	ch := newHasher(iv, flagDeriveKeyContext, Size)
+	ch.Write([]byte(context))
+
+	var contextKey [KeySize]byte
+	ch.XOF().Read(contextKey[:])
+
+	return newHasher(keyWords(contextKey[:]), flagDeriveKeyMaterial, Size)
+}
+
+// Sum256 returns the 32-byte BLAKE3 hash of data.
+func Sum256(data []byte) [Size]byte {
+	h := New()
+	h.Write(data)
+
+	var out [Size]byte
+	h.XOF().Read(out[:])
+	return out
+}
+
+// DeriveKey fills out with key material derived from material in the given
+// context.
+func DeriveKey(context string, material, out []byte) {
+	h := NewDeriveKey(context)
+	h.Write(material)
+	h.XOF().Read(out)
+}
+
+func newHasher(key [8]uint32, flags uint32, size int) *Hasher {
+	return &Hasher{
+		key:   key,
+		flags: flags,
+		size:  size,
+		chunk: newChunkState(key, 0, flags),
+	}
+}
+
+func keyWords(key []byte) [8]uint32 {
+	var words [8]uint32
+	for i := range words {
+		words[i] = binary.LittleEndian.Uint32(key[i*4:])
+	}
+	return words
+}
+
+// Size returns the number of bytes Sum appends.
+func (h *Hasher) Size() int { return h.size }
+
+// BlockSize returns the compression block size.
+func (h *Hasher) BlockSize() int { return BlockSize }
+
+// Reset restores the Hasher to its initial state, keeping key and mode.
+func (h *Hasher) Reset() {
+	h.chunk = newChunkState(h.key, 0, h.flags)
+	h.cvStack = h.cvStack[:0]
+}
+
+// Write adds p to the hash. Writes of many chunks are hashed in parallel.
+func (h *Hasher) Write(p []byte) (int, error) {
+	n := len(p)
+
+	for len(p) > 0 {
+		if h.chunk.len() == chunkLen {
+			counter := h.chunk.counter
+			h.pushCV(h.chunk.output().chainingValue(), counter, 0)
+			h.chunk = newChunkState(h.key, counter+1, h.flags)
+		}
+
+		// At a chunk boundary, hash the largest aligned subtree that
+		// leaves at least one byte for the chunk state, so the final
+		// chunk is always finished with the root flag by Sum.
+		if h.chunk.len() == 0 && len(p) > chunkLen {
+			counter := h.chunk.counter
+			chunks := uint64(1) << (bits.Len64(uint64(len(p)-1)/chunkLen) - 1)
+			for counter&(chunks-1) != 0 {
+				chunks >>= 1
+			}
+
+			if chunks > 1 {
+				size := int(chunks) * chunkLen
+				level := bits.TrailingZeros64(chunks)
+				h.pushCV(subtreeCV(p[:size], h.key, counter, h.flags), counter, level)
+				h.chunk = newChunkState(h.key, counter+chunks, h.flags)
+				p = p[size:]
+				continue
+			}
+		}
+
+		take := chunkLen - h.chunk.len()
+		if take > len(p) {
+			take = len(p)
+		}
+		h.chunk.update(p[:take])
+		p = p[take:]
+	}
+
+	return n, nil
+}
+
+// pushCV adds the chaining value of the complete subtree of 2^level chunks
+// starting at chunk counter and merges every subtree it completes.
+func (h *Hasher) pushCV(cv [8]uint32, counter uint64, level int) {
+	total := (counter >> level) + 1
+	for total&1 == 0 {
+		cv = parentCV(h.cvStack[len(h.cvStack)-1], cv, h.key, h.flags)
+		h.cvStack = h.cvStack[:len(h.cvStack)-1]
+		total >>= 1
+	}
+	h.cvStack = append(h.cvStack, cv)
+}
+
+// rootOutput returns the output of the root node for the current input.
+func (h *Hasher) rootOutput() output {
+	out := h.chunk.output()
+	for i := len(h.cvStack) - 1; i >= 0; i-- {
+		out = parentOutput(h.cvStack[i], out.chainingValue(), h.key, h.flags)
+	}
+	return out
+}
+
+// Sum appends Size() bytes of the hash to b without changing the state.
+func (h *Hasher) Sum(b []byte) []byte {
+	out := make([]byte, h.size)
+	h.XOF().Read(out)
+	return append(b, out...)
+}
+
+// XOF returns a reader of the extendable output for the input written so
+// far. The Hasher can be written to further without affecting the reader.
+func (h *Hasher) XOF() *OutputReader {
+	return &OutputReader{out: h.rootOutput()}
+}
+
+// OutputReader reads BLAKE3 extendable output. It never returns an error
+// before 2^64 bytes.
+type OutputReader struct {
+	out   output
+	pos   uint64
+	block [BlockSize]byte
+}
+
+var _ io.Reader = (*OutputReader)(nil)
+
+// Read fills p with the next len(p) bytes of output.
+func (r *OutputReader) Read(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		off := int(r.pos % BlockSize)
+		if off == 0 {
+			words := r.out.rootBlock(r.pos / BlockSize)
+			for i, w := range words {
+				binary.LittleEndian.PutUint32(r.block[i*4:], w)
+			}
+		}
+
+		c := copy(p, r.block[off:])
+		p = p[c:]
+		r.pos += uint64(c)
+	}
+
+	return n, nil
+}
+
+// subtreeCV returns the chaining value of the complete subtree formed by
+// data, a power of two number of chunks starting at chunk counter. The
+// chunks are hashed on up to GOMAXPROCS goroutines.
+func subtreeCV(data []byte, key [8]uint32, counter uint64, flags uint32) [8]uint32 {
+	chunks := len(data) / chunkLen
+	cvs := make([][8]uint32, chunks)
+
+	workers := runtime.GOMAXPROCS(0)
+	if chunks < parallelChunks || workers == 1 {
+		workers = 1
+	}
+	per := (chunks + workers - 1) / workers
+
+	var wg sync.WaitGroup
+	for start := 0; start < chunks; start += per {
+		end := start + per
+		if end > chunks {
+			end = chunks
+		}
+
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			for i := start; i < end; i++ {
+				cs := newChunkState(key, counter+uint64(i), flags)
+				cs.update(data[i*chunkLen : (i+1)*chunkLen])
+				cvs[i] = cs.output().chainingValue()
+			}
+		}(start, end)
+	}
+	wg.Wait()
+
+	for len(cvs) > 1 {
+		for i := 0; i < len(cvs)/2; i++ {
+			cvs[i] = parentCV(cvs[2*i], cvs[2*i+1], key, flags)
+		}
+		cvs = cvs[:len(cvs)/2]
+	}
+
+	return cvs[0]
+}
+
+// chunkState hashes the blocks of a single chunk.
+type chunkState struct {
+	cv               [8]uint32
+	counter          uint64
+	block            [BlockSize]byte
+	blockLen         int
+	blocksCompressed int
+	flags            uint32
+}
+
+func newChunkState(key [8]uint32, counter uint64, flags uint32) chunkState {
+	return chunkState{cv: key, counter: counter, flags: flags}
+}
+
+func (c *chunkState) len() int {
+	return BlockSize*c.blocksCompressed + c.blockLen
+}
+
+func (c *chunkState) startFlag() uint32 {
+	if c.blocksCompressed == 0 {
+		return flagChunkStart
+	}
+	return 0
+}
+
+func (c *chunkState) update(p []byte) {
+	for len(p) > 0 {
+		if c.blockLen == BlockSize {
+			words := blockWords(&c.block)
+			out := compress(&c.cv, &words, c.counter, BlockSize, c.flags|c.startFlag())
+			copy(c.cv[:], out[:8])
+			c.blocksCompressed++
+			c.block = [BlockSize]byte{}
+			c.blockLen = 0
+		}
+
+		n := copy(c.block[c.blockLen:], p)
+		c.blockLen += n
+		p = p[n:]
+	}
+}
+
+func (c *chunkState) output() output {
+	return output{
+		cv:       c.cv,
+		block:    blockWords(&c.block),
+		counter:  c.counter,
+		blockLen: uint32(c.blockLen),
+		flags:    c.flags | c.startFlag() | flagChunkEnd,
+	}
+}
+
+// output is the last compression of a node, kept unfinished so it can yield
+// either a chaining value or root output.
+type output struct {
+	cv       [8]uint32
+	block    [16]uint32
+	counter  uint64
+	blockLen uint32
+	flags    uint32
+}
+
+func (o output) chainingValue() [8]uint32 {
+	out := compress(&o.cv, &o.block, o.counter, o.blockLen, o.flags)
+	var cv [8]uint32
+	copy(cv[:], out[:8])
+	return cv
+}
+
+func (o output) rootBlock(n uint64) [16]uint32 {
+	return compress(&o.cv, &o.block, n, o.blockLen, o.flags|flagRoot)
+}
+
+func parentOutput(left, right [8]uint32, key [8]uint32, flags uint32) output {
+	var block [16]uint32
+	copy(block[:8], left[:])
+	copy(block[8:], right[:])
+	return output{cv: key, block: block, blockLen: BlockSize, flags: flags | flagParent}
+}
+
+func parentCV(left, right [8]uint32, key [8]uint32, flags uint32) [8]uint32 {
+	return parentOutput(left, right, key, flags).chainingValue()
+}
+
+func blockWords(b *[BlockSize]byte) [16]uint32 {
+	var words [16]uint32
+	for i := range words {
+		words[i] = binary.LittleEndian.Uint32(b[i*4:])
+	}
+	return words
+}
+
+func g(s *[16]uint32, a, b, c, d int, x, y uint32) {
+	s[a] += s[b] + x
+	s[d] = bits.RotateLeft32(s[d]^s[a], -16)
+	s[c] += s[d]
+	s[b] = bits.RotateLeft32(s[b]^s[c], -12)
+	s[a] += s[b] + y
+	s[d] = bits.RotateLeft32(s[d]^s[a], -8)
+	s[c] += s[d]
+	s[b] = bits.RotateLeft32(s[b]^s[c], -7)
+}
+
+// schedule lists the message word order of each of the seven rounds.
+var schedule = [7][16]uint8{
+	{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15},
+	{2, 6, 3, 10, 7, 0, 4, 13, 1, 11, 12, 5, 9, 14, 15, 8},
+	{3, 4, 10, 12, 13, 2, 7, 14, 6, 5, 9, 0, 11, 15, 8, 1},
+	{10, 7, 12, 9, 14, 3, 13, 15, 4, 0, 11, 2, 5, 8, 1, 6},
+	{12, 13, 9, 11, 15, 10, 14, 8, 7, 2, 5, 3, 0, 1, 6, 4},
+	{9, 14, 11, 5, 8, 12, 15, 1, 13, 3, 0, 10, 2, 6, 4, 7},
+	{11, 15, 5, 0, 1, 9, 8, 6, 14, 10, 2, 12, 3, 4, 7, 13},
+}
+
+func compress(cv *[8]uint32, m *[16]uint32, counter uint64, blockLen, flags uint32) [16]uint32 {
+	s := [16]uint32{
+		cv[0], cv[1], cv[2], cv[3], cv[4], cv[5], cv[6], cv[7],
+		iv[0], iv[1], iv[2], iv[3],
+		uint32(counter), uint32(counter >> 32), blockLen, flags,
+	}
+
+	for r := range schedule {
+		w := &schedule[r]
+		g(&s, 0, 4, 8, 12, m[w[0]], m[w[1]])
+		g(&s, 1, 5, 9, 13, m[w[2]], m[w[3]])
+		g(&s, 2, 6, 10, 14, m[w[4]], m[w[5]])
+		g(&s, 3, 7, 11, 15, m[w[6]], m[w[7]])
+		g(&s, 0, 5, 10, 15, m[w[8]], m[w[9]])
+		g(&s, 1, 6, 11, 12, m[w[10]], m[w[11]])
+		g(&s, 2, 7, 8, 13, m[w[12]], m[w[13]])
+		g(&s, 3, 4, 9, 14, m[w[14]], m[w[15]])
+	}
+
+	for i := 0; i < 8; i++ {
+		s[i] ^= s[i+8]
+		s[i+8] ^= cv[i]
+	}
+
+	return s
+}
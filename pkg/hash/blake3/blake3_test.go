@@ -0,0 +1,140 @@
+package blake3_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"runtime"
+	"testing"
+
+	. "github.com/TechMDW/hashit/pkg/hash/blake3"
+)
+
+const (
+	vectorKey     = "whats the Elvish word for friend"
+	vectorContext = "BLAKE3 2019-12-27 16:29:52 test vectors context"
+)
+
+func vectorInput(n int) []byte {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte(i % 251)
+	}
+	return b
+}
+
+// xof returns the first n bytes of the extendable output of h, read in
+// uneven pieces to exercise block boundaries.
+func xof(h *Hasher, n int) string {
+	out := make([]byte, n)
+	r := h.XOF()
+	for i := 0; i < n; i += 7 {
+		end := i + 7
+		if end > n {
+			end = n
+		}
+		r.Read(out[i:end])
+	}
+	return hex.EncodeToString(out)
+}
+
+func TestVectors(t *testing.T) {
+	for _, v := range vectors {
+		input := vectorInput(v.inputLen)
+		n := len(v.hash) / 2
+
+		h := New()
+		h.Write(input)
+		if got := xof(h, n); got != v.hash {
+			t.Errorf("hash(%d): expected %s, got %s", v.inputLen, v.hash, got)
+		}
+		if got := hex.EncodeToString(h.Sum(nil)); got != v.hash[:64] {
+			t.Errorf("Sum(%d): expected %s, got %s", v.inputLen, v.hash[:64], got)
+		}
+
+		k, err := NewKeyed([]byte(vectorKey))
+		if err != nil {
+			t.Fatal(err)
+		}
+		k.Write(input)
+		if got := xof(k, n); got != v.keyedHash {
+			t.Errorf("keyed(%d): expected %s, got %s", v.inputLen, v.keyedHash, got)
+		}
+
+		d := NewDeriveKey(vectorContext)
+		d.Write(input)
+		if got := xof(d, n); got != v.deriveKey {
+			t.Errorf("derive(%d): expected %s, got %s", v.inputLen, v.deriveKey, got)
+		}
+	}
+}
+
+// TestParallel checks that large writes, which are hashed as parallel
+// subtrees, agree with writes smaller than a chunk.
+func TestParallel(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+
+	for _, size := range []int{64 << 10, 1<<20 + 1, 3<<20 + 5000} {
+		input := vectorInput(size)
+
+		serial := New()
+		for i := 0; i < size; i += 1000 {
+			end := i + 1000
+			if end > size {
+				end = size
+			}
+			serial.Write(input[i:end])
+		}
+
+		parallel := New()
+		parallel.Write(input[:size/3])
+		parallel.Write(input[size/3:])
+
+		if !bytes.Equal(serial.Sum(nil), parallel.Sum(nil)) {
+			t.Errorf("size %d: parallel and serial hashes differ", size)
+		}
+	}
+}
+
+func TestResetAndSum(t *testing.T) {
+	h := New()
+	h.Write([]byte("garbage"))
+	h.Reset()
+	h.Write(vectorInput(1025))
+
+	want := vectors[4]
+	if want.inputLen != 1025 {
+		t.Fatalf("unexpected vector order")
+	}
+	if got := hex.EncodeToString(h.Sum(nil)); got != want.hash[:64] {
+		t.Errorf("expected %s after Reset, got %s", want.hash[:64], got)
+	}
+
+	sum := Sum256(vectorInput(1025))
+	if hex.EncodeToString(sum[:]) != want.hash[:64] {
+		t.Errorf("Sum256 mismatch")
+	}
+
+	if got := NewSize(100).Sum(nil); len(got) != 100 || hex.EncodeToString(got) != vectors[0].hash[:200] {
+		t.Errorf("NewSize(100) returned %x", got)
+	}
+
+	if _, err := NewKeyed([]byte("short")); err == nil {
+		t.Error("expected error for short key")
+	}
+
+	out := make([]byte, 32)
+	DeriveKey(vectorContext, vectorInput(0), out)
+	if hex.EncodeToString(out) != vectors[0].deriveKey[:64] {
+		t.Errorf("DeriveKey mismatch")
+	}
+}
+
+func BenchmarkWrite(b *testing.B) {
+	data := vectorInput(4 << 20)
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		h := New()
+		h.Write(data)
+		h.Sum(nil)
+	}
+}
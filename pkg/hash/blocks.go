@@ -0,0 +1,267 @@
+package hash
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// DefaultBlockSize is the block size of a BlockList unless another is
+// given.
+const DefaultBlockSize = 1 << 20
+
+// blockListMagic starts the text form of a BlockList.
+const blockListMagic = "hashit-blocks"
+
+// BlockList holds the digest of every block of a file and of the file as a
+// whole, so that a copy can be checked block by block and only the damaged
+// regions transferred again.
+type BlockList struct {
+	Algorithm string `json:"algorithm"`
+	BlockSize int64  `json:"blockSize"`
+	// Size is the size of the file in bytes.
+	Size int64 `json:"size"`
+	// Root is the hex digest of the whole file, as HashFile computes it.
+	Root string `json:"root"`
+	// Blocks holds the hex digest of each block in order. The last block
+	// is short unless BlockSize divides Size.
+	Blocks []string `json:"blocks"`
+}
+
+// blockHasher splits what is written to it into blocks and records the
+// digest of each.
+type blockHasher struct {
+	hash.Hash
+	blockSize int64
+	// n is the number of bytes written to the current block.
+	n       int64
+	digests []string
+}
+
+func (b *blockHasher) Write(p []byte) (int, error) {
+	total := len(p)
+	for len(p) > 0 {
+		k := min(int64(len(p)), b.blockSize-b.n)
+		b.Hash.Write(p[:k])
+		b.n += k
+		p = p[k:]
+		if b.n == b.blockSize {
+			b.flush()
+		}
+	}
+
+	return total, nil
+}
+
+// flush records the digest of the current block, if it is not empty.
+func (b *blockHasher) flush() {
+	if b.n == 0 {
+		return
+	}
+
+	b.digests = append(b.digests, hex.EncodeToString(b.Hash.Sum(nil)))
+	b.Hash.Reset()
+	b.n = 0
+}
+
+// HashBlocks returns the block list of the file at path with blocks of
+// blockSize bytes hashed with the named algorithm. The whole file and the
+// blocks are hashed in a single read. It stops with a CanceledError once ctx
+// is done, and reports its progress to an observer attached with
+// WithProgress.
+func HashBlocks(ctx context.Context, path, hashType string, blockSize int64) (*BlockList, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return HashBlocksReader(ctx, file, hashType, blockSize)
+}
+
+// HashBlocksReader returns the block list of everything read from r, as
+// HashBlocks does for a file.
+func HashBlocksReader(ctx context.Context, r io.Reader, hashType string, blockSize int64) (*BlockList, error) {
+	if blockSize <= 0 {
+		return nil, fmt.Errorf("invalid block size %d", blockSize)
+	}
+	algo, err := lookup(hashType)
+	if err != nil {
+		return nil, err
+	}
+
+	whole := algo.New()
+	blocks := &blockHasher{Hash: algo.New(), blockSize: blockSize}
+	size, err := feedReader(ctx, r, []hash.Hash{whole, blocks}, nil)
+	if err != nil {
+		return nil, err
+	}
+	blocks.flush()
+
+	return &BlockList{
+		Algorithm: algo.Name,
+		BlockSize: blockSize,
+		Size:      size,
+		Root:      hex.EncodeToString(whole.Sum(nil)),
+		Blocks:    blocks.digests,
+	}, nil
+}
+
+// VerifyBlocks hashes the file at path with the algorithm and block size of
+// list and returns the byte ranges of the file that differ from list, as
+// Diff does. No ranges means the file matches.
+func VerifyBlocks(ctx context.Context, list *BlockList, path string) ([]Range, error) {
+	actual, err := HashBlocks(ctx, path, list.Algorithm, list.BlockSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return list.Diff(actual)
+}
+
+// Diff returns the byte ranges where the file described by other differs
+// from the one described by l: the blocks whose digests differ and, when
+// the sizes differ, everything past the end of the shorter file. Adjacent
+// blocks are merged into one range. Both lists must use the same algorithm
+// and block size.
+func (l *BlockList) Diff(other *BlockList) ([]Range, error) {
+	if l.Algorithm != other.Algorithm || l.BlockSize != other.BlockSize {
+		return nil, fmt.Errorf("cannot compare blocks of %d bytes hashed with %s to blocks of %d bytes hashed with %s",
+			l.BlockSize, l.Algorithm, other.BlockSize, other.Algorithm)
+	}
+
+	end := max(l.Size, other.Size)
+	var ranges []Range
+	for i := 0; i < max(len(l.Blocks), len(other.Blocks)); i++ {
+		if i < len(l.Blocks) && i < len(other.Blocks) && l.Blocks[i] == other.Blocks[i] {
+			continue
+		}
+
+		start := int64(i) * l.BlockSize
+		length := min(l.BlockSize, end-start)
+		if n := len(ranges); n > 0 && ranges[n-1].Offset+ranges[n-1].Length == start {
+			ranges[n-1].Length += length
+			continue
+		}
+		ranges = append(ranges, Range{Offset: start, Length: length})
+	}
+
+	return ranges, nil
+}
+
+// WriteBlockList writes list as text: a header line with the algorithm,
+// block size, file size and root digest, followed by one digest per block.
+func WriteBlockList(w io.Writer, list *BlockList) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "%s %s %d %d %s\n", blockListMagic, list.Algorithm, list.BlockSize, list.Size, list.Root)
+	for _, digest := range list.Blocks {
+		fmt.Fprintln(bw, digest)
+	}
+
+	return bw.Flush()
+}
+
+// ReadBlockList reads a block list written by WriteBlockList or encoded as
+// JSON, and checks that it is consistent.
+func ReadBlockList(r io.Reader) (*BlockList, error) {
+	br := bufio.NewReader(r)
+	first, err := br.Peek(1)
+	if err != nil {
+		return nil, fmt.Errorf("invalid block list: %w", err)
+	}
+
+	var list BlockList
+	if first[0] == '{' {
+		if err := json.NewDecoder(br).Decode(&list); err != nil {
+			return nil, fmt.Errorf("invalid block list: %w", err)
+		}
+	} else if err := parseBlockList(br, &list); err != nil {
+		return nil, err
+	}
+
+	if err := list.check(); err != nil {
+		return nil, fmt.Errorf("invalid block list: %w", err)
+	}
+
+	return &list, nil
+}
+
+// parseBlockList parses the text form of a block list.
+func parseBlockList(r io.Reader, list *BlockList) error {
+	scanner := bufio.NewScanner(r)
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return err
+		}
+		return fmt.Errorf("invalid block list: empty")
+	}
+
+	header := strings.Fields(scanner.Text())
+	if len(header) != 5 || header[0] != blockListMagic {
+		return fmt.Errorf("invalid block list: the first line must be %q", blockListMagic+" ALGORITHM BLOCKSIZE SIZE ROOT")
+	}
+
+	var err error
+	list.Algorithm = header[1]
+	if list.BlockSize, err = strconv.ParseInt(header[2], 10, 64); err != nil {
+		return fmt.Errorf("invalid block list: invalid block size %q", header[2])
+	}
+	if list.Size, err = strconv.ParseInt(header[3], 10, 64); err != nil {
+		return fmt.Errorf("invalid block list: invalid size %q", header[3])
+	}
+	list.Root = header[4]
+
+	for scanner.Scan() {
+		digest := string(bytes.TrimSpace(scanner.Bytes()))
+		if digest == "" {
+			continue
+		}
+		list.Blocks = append(list.Blocks, digest)
+	}
+
+	return scanner.Err()
+}
+
+// check reports whether the list is consistent with its algorithm and
+// size, and normalizes the algorithm name and digests.
+func (l *BlockList) check() error {
+	algo, err := lookup(l.Algorithm)
+	if err != nil {
+		return err
+	}
+	l.Algorithm = algo.Name
+
+	if l.BlockSize <= 0 || l.Size < 0 {
+		return fmt.Errorf("invalid block size %d or size %d", l.BlockSize, l.Size)
+	}
+	if want := (l.Size + l.BlockSize - 1) / l.BlockSize; int64(len(l.Blocks)) != want {
+		return fmt.Errorf("%d bytes in blocks of %d bytes need %d digests, found %d", l.Size, l.BlockSize, want, len(l.Blocks))
+	}
+
+	// Digests are compared as strings, so lower their case.
+	size := algo.New().Size()
+	valid := func(digest string) bool {
+		b, err := hex.DecodeString(digest)
+		return err == nil && len(b) == size
+	}
+	l.Root = strings.ToLower(l.Root)
+	if !valid(l.Root) {
+		return fmt.Errorf("invalid %s root digest %q", algo.Name, l.Root)
+	}
+	for i, digest := range l.Blocks {
+		l.Blocks[i] = strings.ToLower(digest)
+		if !valid(digest) {
+			return fmt.Errorf("invalid %s digest %q of block %d", algo.Name, digest, i)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,174 @@
+package hash_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	. "github.com/TechMDW/hashit/pkg/hash"
+)
+
+func TestHashBlocks(t *testing.T) {
+	const blockSize = 1000
+	data := largeData(BufferSize + 2500)
+
+	list, err := HashBlocksReader(context.Background(), bytes.NewReader(data), "sha256", blockSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root := sha256.Sum256(data)
+	if list.Root != hex.EncodeToString(root[:]) {
+		t.Errorf("Expected root %x, got %s", root, list.Root)
+	}
+	if list.Size != int64(len(data)) || list.BlockSize != blockSize || list.Algorithm != "sha256" {
+		t.Errorf("Unexpected block list header %+v", list)
+	}
+
+	if want := (len(data) + blockSize - 1) / blockSize; len(list.Blocks) != want {
+		t.Fatalf("Expected %d blocks, got %d", want, len(list.Blocks))
+	}
+	for _, i := range []int{0, 4194, len(list.Blocks) - 1} {
+		block := sha256.Sum256(data[i*blockSize : min((i+1)*blockSize, len(data))])
+		if list.Blocks[i] != hex.EncodeToString(block[:]) {
+			t.Errorf("Expected block %d to be %x, got %s", i, block, list.Blocks[i])
+		}
+	}
+
+	empty, err := HashBlocksReader(context.Background(), bytes.NewReader(nil), "sha256", blockSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(empty.Blocks) != 0 || empty.Size != 0 {
+		t.Errorf("Expected no blocks for empty input, got %+v", empty)
+	}
+
+	if _, err := HashBlocksReader(context.Background(), bytes.NewReader(data), "sha256", 0); err == nil {
+		t.Error("Expected an error for a block size of 0")
+	}
+}
+
+func TestVerifyBlocks(t *testing.T) {
+	const blockSize = 1024
+	data := largeData(10*blockSize + 100)
+	filePath := filepath.Join(t.TempDir(), "vm.img")
+
+	list, err := HashBlocksReader(context.Background(), bytes.NewReader(data), "sha256", blockSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		modify   func([]byte) []byte
+		expected []Range
+	}{
+		{
+			name:   "unchanged",
+			modify: func(b []byte) []byte { return b },
+		},
+		{
+			name: "one byte",
+			modify: func(b []byte) []byte {
+				b[3*blockSize+17] ^= 1
+				return b
+			},
+			expected: []Range{{Offset: 3 * blockSize, Length: blockSize}},
+		},
+		{
+			name: "adjacent blocks merged",
+			modify: func(b []byte) []byte {
+				b[2*blockSize] ^= 1
+				b[3*blockSize] ^= 1
+				b[7*blockSize] ^= 1
+				return b
+			},
+			expected: []Range{{Offset: 2 * blockSize, Length: 2 * blockSize}, {Offset: 7 * blockSize, Length: blockSize}},
+		},
+		{
+			name:     "truncated",
+			modify:   func(b []byte) []byte { return b[:8*blockSize+5] },
+			expected: []Range{{Offset: 8 * blockSize, Length: 2*blockSize + 100}},
+		},
+		{
+			name:     "extended",
+			modify:   func(b []byte) []byte { return append(b, make([]byte, 2*blockSize)...) },
+			expected: []Range{{Offset: 10 * blockSize, Length: 2*blockSize + 100}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := os.WriteFile(filePath, test.modify(bytes.Clone(data)), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			ranges, err := VerifyBlocks(context.Background(), list, filePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(ranges, test.expected) {
+				t.Errorf("Expected %+v, got %+v", test.expected, ranges)
+			}
+		})
+	}
+
+	other := *list
+	other.BlockSize = 2 * blockSize
+	if _, err := list.Diff(&other); err == nil {
+		t.Error("Expected an error comparing lists with different block sizes")
+	}
+}
+
+func TestBlockListEncoding(t *testing.T) {
+	list, err := HashBlocksReader(context.Background(), bytes.NewReader(largeData(2500)), "md5", 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var text bytes.Buffer
+	if err := WriteBlockList(&text, list); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(text.String(), "hashit-blocks md5 1000 2500 "+list.Root+"\n") {
+		t.Errorf("Unexpected header in %q", text.String())
+	}
+
+	j, err := json.Marshal(list)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, encoded := range map[string]string{"text": text.String(), "json": string(j)} {
+		t.Run(name, func(t *testing.T) {
+			got, err := ReadBlockList(strings.NewReader(encoded))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, list) {
+				t.Errorf("Expected %+v, got %+v", list, got)
+			}
+		})
+	}
+
+	invalid := map[string]string{
+		"empty":          "",
+		"bad header":     "sha256 1000 2500\n",
+		"unknown hash":   "hashit-blocks nope 1000 2500 " + list.Root + "\n",
+		"missing block":  strings.Join(strings.Split(text.String(), "\n")[:3], "\n"),
+		"digest size":    strings.Replace(text.String(), list.Blocks[1], "abcd", 1),
+		"bad block size": "hashit-blocks md5 0 0 " + list.Root + "\n",
+	}
+	for name, encoded := range invalid {
+		if _, err := ReadBlockList(strings.NewReader(encoded)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package hash
+
+import (
+	"context"
+	"fmt"
+)
+
+// CanceledError is returned when hashing stops because its context was
+// canceled or its deadline passed. Err is the cause of the cancellation as
+// reported by context.Cause, which is ctx.Err() unless a cause was given, so
+// errors.Is(err, context.Canceled) and errors.Is(err,
+// context.DeadlineExceeded) work as usual.
+type CanceledError struct {
+	// Processed is the number of input bytes hashed before stopping.
+	Processed int64
+	// Files is the number of files hashed completely by functions that hash
+	// several files, such as HashFiles and HashTree.
+	Files int
+	Err   error
+}
+
+func (e *CanceledError) Error() string {
+	switch {
+	case e.Files > 0:
+		return fmt.Sprintf("hashing stopped after %d file(s), %d bytes: %v", e.Files, e.Processed, e.Err)
+	case e.Processed > 0:
+		return fmt.Sprintf("hashing stopped after %d bytes: %v", e.Processed, e.Err)
+	default:
+		return fmt.Sprintf("hashing stopped: %v", e.Err)
+	}
+}
+
+func (e *CanceledError) Unwrap() error {
+	return e.Err
+}
+
+// canceled returns a CanceledError recording processed bytes once ctx is
+// done, and nil otherwise.
+func canceled(ctx context.Context, processed int64) error {
+	if ctx.Err() == nil {
+		return nil
+	}
+
+	return &CanceledError{Processed: processed, Err: context.Cause(ctx)}
+}
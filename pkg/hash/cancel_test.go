@@ -0,0 +1,115 @@
+package hash_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/TechMDW/hashit/pkg/hash"
+)
+
+// cancelingReader cancels its context once more than after bytes were read.
+type cancelingReader struct {
+	r      io.Reader
+	n      int
+	after  int
+	cancel context.CancelFunc
+}
+
+func (c *cancelingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += n
+	if c.n > c.after {
+		c.cancel()
+	}
+	return n, err
+}
+
+func TestHashReaderCanceledMidway(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	r := &cancelingReader{r: bytes.NewReader(largeData(4 * BufferSize)), after: BufferSize, cancel: cancel}
+	_, err := HashReader(ctx, r, "sha256")
+
+	var canceled *CanceledError
+	if !errors.As(err, &canceled) {
+		t.Fatalf("Expected a CanceledError, got %v", err)
+	}
+	if canceled.Processed != BufferSize {
+		t.Errorf("Expected %d bytes processed, got %d", BufferSize, canceled.Processed)
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the error to wrap context.Canceled, got %v", err)
+	}
+}
+
+func TestHasherMultiCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := HasherMulti(ctx, largeData(2*BufferSize), "sha256,md5")
+
+	var canceled *CanceledError
+	if !errors.As(err, &canceled) || canceled.Processed != 0 {
+		t.Fatalf("Expected a CanceledError without progress, got %v", err)
+	}
+}
+
+func TestComputeHashTimeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), -time.Second)
+	defer cancel()
+
+	if _, err := ComputeHash(ctx, largeData(BufferSize+1), "sha256", false); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestCanceledCause(t *testing.T) {
+	cause := errors.New("stopped by test")
+	ctx, cancel := context.WithCancelCause(context.Background())
+	cancel(cause)
+
+	if _, err := HasherMulti(ctx, largeData(BufferSize+1), "md5"); !errors.Is(err, cause) {
+		t.Fatalf("Expected the cancellation cause, got %v", err)
+	}
+}
+
+func TestHashFilesCanceled(t *testing.T) {
+	dir := t.TempDir()
+	var files []FileEntry
+	for _, name := range []string{"a", "b", "c"} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte("test data"), 0644); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+		files = append(files, FileEntry{Path: name, OSPath: path})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := HashFiles(ctx, files, []string{"sha256"}, 1)
+
+	var canceled *CanceledError
+	if !errors.As(err, &canceled) {
+		t.Fatalf("Expected a CanceledError, got %v", err)
+	}
+	if canceled.Files != 0 || canceled.Processed != 0 {
+		t.Errorf("Expected no progress, got %d files and %d bytes", canceled.Files, canceled.Processed)
+	}
+}
+
+func TestSelfTestCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if results, err := SelfTest(ctx); !errors.Is(err, context.Canceled) || len(results) != 0 {
+		t.Fatalf("Expected context.Canceled and no results, got %d results and %v", len(results), err)
+	}
+}
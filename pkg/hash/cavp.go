@@ -0,0 +1,321 @@
+package hash
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// CAVPResult is the outcome of one vector of a CAVP response file.
+type CAVPResult struct {
+	// Line is the line of the expected digest.
+	Line int `json:"line"`
+	// Vector names the vector, e.g. "Len = 8" or "COUNT = 3".
+	Vector   string `json:"vector"`
+	Passed   bool   `json:"passed"`
+	Expected string `json:"expected,omitempty"`
+	Got      string `json:"got,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// CAVPReport holds the outcome of every vector of a CAVP response file.
+type CAVPReport struct {
+	// Algorithm is the algorithm the vectors were run with.
+	Algorithm string       `json:"algorithm"`
+	Results   []CAVPResult `json:"results"`
+}
+
+// cavpNames maps the algorithm names of the CAVP files, without "-", "_",
+// "/" and spaces, to hashit algorithms.
+var cavpNames = map[string]string{
+	"SHA1":      "sha1",
+	"SHA224":    "sha224",
+	"SHA256":    "sha256",
+	"SHA384":    "sha384",
+	"SHA512":    "sha512",
+	"SHA512224": "sha512_224",
+	"SHA512256": "sha512_256",
+	"SHA3224":   "sha3_224",
+	"SHA3256":   "sha3_256",
+	"SHA3384":   "sha3_384",
+	"SHA3512":   "sha3_512",
+	"SHAKE128":  "shake128",
+	"SHAKE256":  "shake256",
+}
+
+// CAVPAlgorithm returns the algorithm tested by a CAVP response file from
+// its file name, like "SHA3_256ShortMsg.rsp", or from the algorithm named in
+// its header, like "SHA-512/224".
+func CAVPAlgorithm(name string) (string, error) {
+	key := name
+	if ext := filepath.Ext(name); strings.EqualFold(ext, ".rsp") {
+		key = strings.TrimSuffix(filepath.Base(name), ext)
+	}
+	key = strings.ToUpper(key)
+	for _, suffix := range []string{"SHORTMSG", "LONGMSG", "MONTE", "VARIABLEOUT"} {
+		key = strings.TrimSuffix(key, suffix)
+	}
+	key = strings.NewReplacer("-", "", "_", "", "/", "", " ", "").Replace(key)
+
+	if algo, ok := cavpNames[key]; ok {
+		return algo, nil
+	}
+
+	return "", fmt.Errorf("cannot tell the algorithm of %s", name)
+}
+
+// cavpRun holds the state of RunCAVP while reading a response file.
+type cavpRun struct {
+	algo    *Algorithm
+	headers map[string]string
+	// record holds the fields of the current vector.
+	record map[string]string
+	// seed is the current Monte Carlo seed, nil outside Monte Carlo files.
+	seed []byte
+	// outLen is the SHAKE Monte Carlo output length in bytes.
+	outLen int
+}
+
+// RunCAVP reads a NIST CAVP response file (.rsp) of the SHA-1, SHA-2, SHA-3
+// or SHAKE validation systems and checks every vector: short and long
+// messages, SHAKE variable output and Monte Carlo tests. When hashType is
+// empty the algorithm is taken from the header comment of the file. Messages
+// whose length is not a whole number of bytes are reported as failed. It
+// stops between vectors with a CanceledError once ctx is done, returning the
+// vectors checked so far.
+func RunCAVP(ctx context.Context, r io.Reader, hashType string) (*CAVPReport, error) {
+	run := &cavpRun{headers: map[string]string{}, record: map[string]string{}}
+	if hashType != "" {
+		a, err := lookup(hashType)
+		if err != nil {
+			return nil, err
+		}
+		run.algo = a
+	}
+
+	report := &CAVPReport{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, "#"):
+			// #  "SHA-256 ShortMsg" information for "sha_values"
+			if run.algo == nil {
+				_, quoted, _ := strings.Cut(line, `"`)
+				if fields := strings.Fields(quoted); len(fields) > 0 {
+					if name, err := CAVPAlgorithm(fields[0]); err == nil {
+						run.algo, _ = lookup(name)
+					}
+				}
+			}
+			continue
+		case strings.HasPrefix(line, "["):
+			key, value, _ := strings.Cut(strings.Trim(line, "[]"), "=")
+			run.headers[strings.ToLower(strings.TrimSpace(key))] = strings.TrimSpace(value)
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: invalid line %q", lineNo, line)
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch {
+		case key == "seed", key == "msg" && len(run.record) == 0 && run.headers["minimum output length (bits)"] != "":
+			// SHAKE Monte Carlo files give the seed as a message
+			// before the first COUNT.
+			seed, err := hex.DecodeString(value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid seed: %w", lineNo, err)
+			}
+			run.seed = seed
+			run.outLen = 0
+		case key == "md" || key == "output":
+			if run.algo == nil {
+				return nil, fmt.Errorf("cannot tell the algorithm of the file, give the hash type")
+			}
+			if err := canceled(ctx, 0); err != nil {
+				report.Algorithm = run.algo.Name
+				return report, err
+			}
+			res := run.check(strings.ToLower(value))
+			res.Line = lineNo
+			report.Results = append(report.Results, res)
+			run.record = map[string]string{}
+		default:
+			run.record[key] = value
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if run.algo != nil {
+		report.Algorithm = run.algo.Name
+	}
+	return report, nil
+}
+
+// check computes the vector of the current record and compares it with
+// expected.
+func (run *cavpRun) check(expected string) CAVPResult {
+	var res CAVPResult
+	if count, ok := run.record["count"]; ok {
+		res.Vector = "COUNT = " + count
+	} else {
+		res.Vector = "Len = " + run.record["len"]
+	}
+
+	var got []byte
+	var err error
+	if msg, ok := run.record["msg"]; ok {
+		got, err = run.message(msg)
+	} else if run.seed != nil {
+		got, err = run.monte()
+	} else {
+		err = fmt.Errorf("vector has neither a message nor a seed")
+	}
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+
+	res.Expected = expected
+	res.Got = hex.EncodeToString(got)
+	res.Passed = res.Got == expected
+	return res
+}
+
+// message computes a short message, long message or variable output vector.
+func (run *cavpRun) message(msgHex string) ([]byte, error) {
+	msg, err := hex.DecodeString(msgHex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid message: %w", err)
+	}
+
+	if l, ok := run.record["len"]; ok {
+		bits, err := strconv.Atoi(l)
+		if err != nil || bits < 0 || bits > 8*len(msg) {
+			return nil, fmt.Errorf("invalid message length %q", l)
+		}
+		if bits%8 != 0 {
+			return nil, fmt.Errorf("bit-oriented messages are not supported")
+		}
+		msg = msg[:bits/8]
+	}
+
+	size := run.algo.Size
+	if run.shake() {
+		l, ok := run.record["outputlen"]
+		if !ok {
+			l, ok = run.headers["outputlen"]
+		}
+		if ok {
+			bits, err := strconv.Atoi(l)
+			if err != nil || bits <= 0 || bits%8 != 0 {
+				return nil, fmt.Errorf("invalid output length %q", l)
+			}
+			size = bits / 8
+		}
+	}
+
+	return run.digest(msg, size)
+}
+
+// shake reports whether the file tests SHAKE128 or SHAKE256, whose vectors
+// give the output length.
+func (run *cavpRun) shake() bool {
+	return run.algo.Family == "sha3" && run.algo.NewXOF != nil
+}
+
+// digest returns the digest of msg, size bytes long for XOFs.
+func (run *cavpRun) digest(msg []byte, size int) ([]byte, error) {
+	if !run.shake() {
+		h := run.algo.New()
+		h.Write(msg)
+		return h.Sum(nil), nil
+	}
+
+	x, err := run.algo.NewXOF(nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	x.Write(msg)
+	out := make([]byte, size)
+	_, err = io.ReadFull(x, out)
+	return out, err
+}
+
+// monte runs one outer iteration of the Monte Carlo test from the current
+// seed and makes the result the next seed.
+func (run *cavpRun) monte() ([]byte, error) {
+	switch {
+	case run.shake():
+		return run.monteSHAKE()
+	case run.algo.Family == "sha3":
+		// SHA3VS: MD_i = SHA3(MD_i-1).
+		md := run.seed
+		for i := 0; i < 1000; i++ {
+			md, _ = run.digest(md, 0)
+		}
+		run.seed = md
+		return md, nil
+	}
+
+	// SHAVS: MD_i = SHA(MD_i-3 || MD_i-2 || MD_i-1), starting from three
+	// copies of the seed.
+	a, b, c := run.seed, run.seed, run.seed
+	for i := 0; i < 1000; i++ {
+		h := run.algo.New()
+		h.Write(a)
+		h.Write(b)
+		h.Write(c)
+		a, b, c = b, c, h.Sum(nil)
+	}
+	run.seed = c
+	return c, nil
+}
+
+// monteSHAKE runs one outer iteration of the SHAKE Monte Carlo test of
+// SHA3VS, where each message is the first 128 bits of the previous output and
+// the last 16 bits of the output choose the next output length.
+func (run *cavpRun) monteSHAKE() ([]byte, error) {
+	minBits, err1 := strconv.Atoi(run.headers["minimum output length (bits)"])
+	maxBits, err2 := strconv.Atoi(run.headers["maximum output length (bits)"])
+	if err1 != nil || err2 != nil || minBits <= 0 || maxBits < minBits {
+		return nil, fmt.Errorf("missing or invalid output length range")
+	}
+	minLen, maxLen := minBits/8, maxBits/8
+	if run.outLen == 0 {
+		run.outLen = maxLen
+	}
+
+	out := run.seed
+	for i := 0; i < 1000; i++ {
+		var msg [16]byte
+		copy(msg[:], out)
+
+		var err error
+		out, err = run.digest(msg[:], run.outLen)
+		if err != nil {
+			return nil, err
+		}
+
+		right := int(binary.BigEndian.Uint16(out[len(out)-2:]))
+		run.outLen = minLen + right%(maxLen-minLen+1)
+	}
+	run.seed = out
+	return out, nil
+}
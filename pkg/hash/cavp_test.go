@@ -0,0 +1,154 @@
+package hash_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	. "github.com/TechMDW/hashit/pkg/hash"
+)
+
+// The vectors below are the first ones of the NIST CAVP files named in the
+// header comments. Later Monte Carlo counts were computed with Python.
+var cavpFiles = map[string]struct {
+	hashType string
+	rsp      string
+	vectors  int
+}{
+	"SHA256ShortMsg.rsp": {"", `#  CAVS 11.0
+#  "SHA-256 ShortMsg" information for "sha_values"
+[L = 32]
+
+Len = 0
+Msg = 00
+MD = e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855
+
+Len = 8
+Msg = d3
+MD = 28969cdfa74a12c82f3bad960b0b000aca2ac329deea5c2328ebc6f2ba9802c1
+
+Len = 16
+Msg = 11af
+MD = 5ca7133fa735326081558ac312c620eeca9970d1e70a4b95533d956f072d1f98
+`, 3},
+	"SHA1Monte.rsp": {"", `#  "SHA1 Monte" information for "sha_values"
+[L = 20]
+
+Seed = dd4df644eaf3d85bace2b21accaa22b28821f5cd
+
+COUNT = 0
+MD = 11f5c38b4479d4ad55cb69fadf62de0b036d5163
+
+COUNT = 1
+MD = 5c26de848c21586bec36995809cb02d3677423d9
+`, 2},
+	"SHA3_256ShortMsg.rsp": {"sha3-256", `[L = 256]
+
+Len = 8
+Msg = e9
+MD = f0d04dd1e6cfc29a4460d521796852f25d9ef8d28b44ee91ff5b759d72c1e6d6
+`, 1},
+	"SHA3_256Monte.rsp": {"", `#  "SHA3-256 Monte" information for "SHA3AllBytes1-28-16"
+[L = 256]
+
+Seed = aa64f7245e2177c654eb4de360da8761a516fdc7578c3498c5e582e096b8730c
+
+COUNT = 0
+MD = 225cbac2be6f329d94228c5360a1c177bc495a761c442a1771b1d18555c309a5
+
+COUNT = 1
+MD = 96d364a1b1ced3dbbce6380093fb1ac77221abcee30faf16546ffad8fe1eef8c
+`, 2},
+	"SHAKE128ShortMsg.rsp": {"", `#  "SHAKE128 ShortMsg" information for "SHAKE3AllBytesGT"
+[Outputlen = 128]
+
+Len = 0
+Msg = 00
+Output = 7f9c2ba4e88f827d616045507605853e
+`, 1},
+	"SHAKE128VariableOut.rsp": {"", `#  "SHAKE128 VariableOut" information for "SHAKE3AllBytesGT"
+[Tested for Output of byte-oriented messages]
+[Input Length = 128]
+[Minimum Output Length (bits) = 128]
+[Maximum Output Length (bits) = 1120]
+
+COUNT = 0
+Outputlen = 128
+Msg = 84e950051876050dc851fbd99e6247b8
+Output = 8599bd89f63a848c49ca593ec37a12c6
+`, 1},
+	"SHAKE128Monte.rsp": {"", `#  "SHAKE128 Monte" information for "SHAKE3AllBytesGT"
+[Minimum Output Length (bits) = 128]
+[Maximum Output Length (bits) = 1120]
+
+Msg = c8b310cb97efa3855434998fa81c7674
+
+COUNT = 0
+Outputlen = 264
+Output = fe8c476993b47b10c98303a04c6212dfb341426d748d3926140aee0a151fc80fa1
+
+COUNT = 1
+Outputlen = 840
+Output = 0ed1e47c5a33592d182ccb6a28cac9b11d23d8038ddebbdd4ae6c584d7ec14269810b082a27655d073ac9bfda81650e18d972e5e96cf1b4279af91cf0bf61156ebf6f042fb70ba6f25be976880c257405e759e71790c5218d05985f5ffff05f9eb2da24053cb7df667
+`, 2},
+}
+
+func TestRunCAVP(t *testing.T) {
+	for name, file := range cavpFiles {
+		t.Run(name, func(t *testing.T) {
+			report, err := RunCAVP(context.Background(), strings.NewReader(file.rsp), file.hashType)
+			if err != nil {
+				t.Fatalf("RunCAVP failed: %v", err)
+			}
+			if expected, _ := CAVPAlgorithm(name); report.Algorithm != expected {
+				t.Errorf("Expected algorithm %s, got %s", expected, report.Algorithm)
+			}
+			results := report.Results
+			if len(results) != file.vectors {
+				t.Fatalf("Expected %d vectors, got %d", file.vectors, len(results))
+			}
+			for _, res := range results {
+				if !res.Passed {
+					t.Errorf("%s (line %d) failed: expected %s, got %s %s", res.Vector, res.Line, res.Expected, res.Got, res.Error)
+				}
+			}
+		})
+	}
+}
+
+func TestRunCAVPFailure(t *testing.T) {
+	rsp := "[L = 32]\n\nLen = 8\nMsg = d3\nMD = 28969cdfa74a12c82f3bad960b0b000aca2ac329deea5c2328ebc6f2ba9802c2\n\nLen = 3\nMsg = 60\nMD = 00\n"
+	report, err := RunCAVP(context.Background(), strings.NewReader(rsp), "sha256")
+	if err != nil {
+		t.Fatalf("RunCAVP failed: %v", err)
+	}
+	results := report.Results
+	if len(results) != 2 || results[0].Passed || results[0].Line != 5 || results[1].Passed || results[1].Error == "" {
+		t.Errorf("Expected two failed vectors, got %+v", results)
+	}
+
+	if _, err := RunCAVP(context.Background(), strings.NewReader(rsp), ""); err == nil {
+		t.Error("Expected error for a file without algorithm, got nil")
+	}
+}
+
+func TestCAVPAlgorithm(t *testing.T) {
+	tests := map[string]string{
+		"SHA256ShortMsg.rsp":          "sha256",
+		"vectors/SHA512_224Monte.rsp": "sha512_224",
+		"SHA3_384LongMsg.rsp":         "sha3_384",
+		"SHAKE256VariableOut.rsp":     "shake256",
+		"SHA-512/256":                 "sha512_256",
+	}
+
+	for name, expected := range tests {
+		algo, err := CAVPAlgorithm(name)
+		if err != nil || algo != expected {
+			t.Errorf("CAVPAlgorithm(%q) = %q, %v, expected %q", name, algo, err, expected)
+		}
+	}
+
+	if _, err := CAVPAlgorithm("MD5.rsp"); err == nil {
+		t.Error("Expected error for MD5.rsp, got nil")
+	}
+}
@@ -0,0 +1,266 @@
+package hash
+
+import (
+	"context"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// DefaultCheckpointInterval is the time between checkpoints unless another
+// is given.
+const DefaultCheckpointInterval = 10 * time.Second
+
+// checkpointVersion is the version of the checkpoint file format.
+const checkpointVersion = 1
+
+// CheckpointOptions configures resumable hashing.
+type CheckpointOptions struct {
+	// Path is the checkpoint file.
+	Path string
+	// Interval is the minimum time between checkpoints,
+	// DefaultCheckpointInterval when zero.
+	Interval time.Duration
+	// Resume continues from the checkpoint in Path. Without a checkpoint
+	// hashing starts from the beginning.
+	Resume bool
+}
+
+// checkpoint is the state of hashing a file part way through, stored as
+// JSON.
+type checkpoint struct {
+	Version int `json:"version"`
+	// File, Size and ModTime identify the file, which must not change
+	// between checkpoint and resume.
+	File    string    `json:"file"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"modTime"`
+	// Offset is the number of bytes of the file hashed.
+	Offset     int64    `json:"offset"`
+	Algorithms []string `json:"algorithms"`
+	// States holds the marshalled state of each algorithm.
+	States [][]byte `json:"states"`
+}
+
+// HashFileCheckpoint returns the hash of the file at path with the named
+// algorithm, as ComputeHash does, saving the state of the hash and the
+// offset reached to opts.Path every opts.Interval. When hashing stops
+// because of an error or ctx, a last checkpoint is saved so that hashing can
+// continue with opts.Resume. The checkpoint is removed once the file is
+// hashed. Algorithms whose state cannot be saved are refused.
+func HashFileCheckpoint(ctx context.Context, path, hashType string, opts CheckpointOptions) (*GenericHash, error) {
+	timeStart := time.Now()
+
+	algo, err := lookup(hashType)
+	if err != nil {
+		return &GenericHash{}, err
+	}
+
+	h := algo.New()
+	if err := hashFileCheckpoint(ctx, path, []*Algorithm{algo}, []hash.Hash{h}, opts); err != nil {
+		return nil, err
+	}
+
+	gh := &GenericHash{Input: []byte(path), Algorithm: algo.Name}
+	gh.HashBytes = h.Sum(nil)
+	gh.HexDigest = fmt.Sprintf("%x", gh.HashBytes)
+	gh.Size = len(gh.HashBytes)
+	timeSince := time.Since(timeStart)
+	gh.Duration = timeSince.Milliseconds()
+	gh.DurationStr = timeSince.String()
+
+	return gh, nil
+}
+
+// HasherMultiFileCheckpoint hashes the file at path with the selected
+// algorithms as HasherMultiFile does, saving checkpoints as
+// HashFileCheckpoint does.
+func HasherMultiFileCheckpoint(ctx context.Context, path string, opts CheckpointOptions, algos ...string) (Hashes, error) {
+	timeStart := time.Now()
+
+	selected, err := resolveAlgorithms(algos)
+	if err != nil {
+		return Hashes{}, err
+	}
+
+	hashers, hashes := initializeHashers(selected)
+	if err := hashFileCheckpoint(ctx, path, selected, hashers, opts); err != nil {
+		return Hashes{}, err
+	}
+
+	setHashes(hashes, hashers)
+	timeSince := time.Since(timeStart)
+	hashes.Duration = timeSince.Milliseconds()
+	hashes.DurationStr = timeSince.String()
+	return *hashes, nil
+}
+
+// hashFileCheckpoint writes the file at path to the hashers of algos, saving
+// and resuming checkpoints as configured by opts.
+func hashFileCheckpoint(ctx context.Context, path string, algos []*Algorithm, hashers []hash.Hash, opts CheckpointOptions) error {
+	if opts.Path == "" {
+		return fmt.Errorf("no checkpoint file given")
+	}
+
+	names := make([]string, len(algos))
+	var unsupported []string
+	for i, a := range algos {
+		names[i] = a.Name
+		_, marshaler := hashers[i].(encoding.BinaryMarshaler)
+		_, unmarshaler := hashers[i].(encoding.BinaryUnmarshaler)
+		if !marshaler || !unmarshaler {
+			unsupported = append(unsupported, a.Name)
+		}
+	}
+	if len(unsupported) > 0 {
+		return fmt.Errorf("cannot checkpoint %s: the hash state cannot be saved", strings.Join(unsupported, ", "))
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	var offset int64
+	if opts.Resume {
+		offset, err = resumeCheckpoint(opts.Path, path, info, names, hashers)
+		if err != nil {
+			return err
+		}
+		if _, err := file.Seek(offset, io.SeekStart); err != nil {
+			return err
+		}
+	}
+
+	save := func(processed int64) error {
+		cp := checkpoint{
+			Version:    checkpointVersion,
+			File:       filepath.Base(path),
+			Size:       info.Size(),
+			ModTime:    info.ModTime(),
+			Offset:     offset + processed,
+			Algorithms: names,
+			States:     make([][]byte, len(hashers)),
+		}
+		for i, h := range hashers {
+			state, err := h.(encoding.BinaryMarshaler).MarshalBinary()
+			if err != nil {
+				return fmt.Errorf("cannot checkpoint %s: %w", names[i], err)
+			}
+			cp.States[i] = state
+		}
+		return writeCheckpoint(opts.Path, &cp)
+	}
+
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = DefaultCheckpointInterval
+	}
+	last := time.Now()
+
+	processed, err := feedReader(ctx, file, hashers, func(processed int64) error {
+		if time.Since(last) < interval {
+			return nil
+		}
+		last = time.Now()
+		return save(processed)
+	})
+	if err != nil {
+		if offset+processed == 0 {
+			return err
+		}
+		if saveErr := save(processed); saveErr != nil {
+			return errors.Join(err, saveErr)
+		}
+		return fmt.Errorf("%w (checkpoint saved at byte %d)", err, offset+processed)
+	}
+
+	if err := os.Remove(opts.Path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+// resumeCheckpoint restores the hashers from the checkpoint at cpPath for
+// the file at path and returns the offset to continue from. It returns 0
+// when there is no checkpoint.
+func resumeCheckpoint(cpPath, path string, info fs.FileInfo, names []string, hashers []hash.Hash) (int64, error) {
+	b, err := os.ReadFile(cpPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	var cp checkpoint
+	if err := json.Unmarshal(b, &cp); err != nil {
+		return 0, fmt.Errorf("invalid checkpoint %s: %w", cpPath, err)
+	}
+
+	switch {
+	case cp.Version != checkpointVersion:
+		return 0, fmt.Errorf("checkpoint %s has unsupported version %d", cpPath, cp.Version)
+	case !slices.Equal(cp.Algorithms, names) || len(cp.States) != len(hashers):
+		return 0, fmt.Errorf("checkpoint %s is for %s, not %s", cpPath, strings.Join(cp.Algorithms, ","), strings.Join(names, ","))
+	case cp.File != filepath.Base(path):
+		return 0, fmt.Errorf("checkpoint %s is for %s, not %s", cpPath, cp.File, filepath.Base(path))
+	case cp.Size != info.Size() || !cp.ModTime.Equal(info.ModTime()):
+		return 0, fmt.Errorf("checkpoint %s does not match the file: it was modified since", cpPath)
+	case cp.Offset < 0 || cp.Offset > cp.Size:
+		return 0, fmt.Errorf("checkpoint %s has invalid offset %d", cpPath, cp.Offset)
+	}
+
+	for i, h := range hashers {
+		if err := h.(encoding.BinaryUnmarshaler).UnmarshalBinary(cp.States[i]); err != nil {
+			return 0, fmt.Errorf("cannot restore %s from checkpoint %s: %w", names[i], cpPath, err)
+		}
+	}
+
+	return cp.Offset, nil
+}
+
+// writeCheckpoint replaces the checkpoint at path. It writes a temporary
+// file first, so a crash never leaves a truncated checkpoint behind.
+func writeCheckpoint(path string, cp *checkpoint) error {
+	b, err := json.MarshalIndent(cp, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,124 @@
+package hash_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/TechMDW/hashit/pkg/hash"
+)
+
+// interruptedCheckpoint hashes filePath with a checkpoint until the first
+// chunk is done and returns the checkpoint options to resume with.
+func interruptedCheckpoint(t *testing.T, filePath string, algos ...string) CheckpointOptions {
+	t.Helper()
+
+	opts := CheckpointOptions{Path: filepath.Join(t.TempDir(), "checkpoint.json")}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ctx = WithProgress(ctx, ProgressFunc(func(Progress) { cancel() }), 0)
+
+	_, err := HasherMultiFileCheckpoint(ctx, filePath, opts, algos...)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "checkpoint saved at byte") {
+		t.Errorf("Expected the error to mention the checkpoint, got %v", err)
+	}
+	if _, err := os.Stat(opts.Path); err != nil {
+		t.Fatalf("Expected a checkpoint: %v", err)
+	}
+
+	opts.Resume = true
+	return opts
+}
+
+func TestHasherMultiFileCheckpointResume(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "large")
+	if err := os.WriteFile(filePath, largeData(3*BufferSize+12345), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	algos := "md5,sha256,sha512,blake2b256,crc32,xxh64"
+	opts := interruptedCheckpoint(t, filePath, algos)
+
+	resumed, err := HasherMultiFileCheckpoint(context.Background(), filePath, opts, algos)
+	if err != nil {
+		t.Fatalf("Resuming failed: %v", err)
+	}
+	expected, err := HasherMultiFile(context.Background(), filePath, algos)
+	if err != nil {
+		t.Fatalf("HasherMultiFile failed: %v", err)
+	}
+	compareHashes(t, resumed, expected)
+
+	if _, err := os.Stat(opts.Path); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected the checkpoint to be removed, got %v", err)
+	}
+}
+
+func TestHashFileCheckpoint(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "test")
+	if err := os.WriteFile(filePath, []byte("test data"), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	// Resuming without a checkpoint starts from the beginning.
+	opts := CheckpointOptions{Path: filepath.Join(t.TempDir(), "checkpoint.json"), Resume: true}
+	gh, err := HashFileCheckpoint(context.Background(), filePath, "sha256", opts)
+	if err != nil {
+		t.Fatalf("HashFileCheckpoint failed: %v", err)
+	}
+	if gh.HexDigest != expectedHashesMap["sha256"] {
+		t.Errorf("Expected %s, got %s", expectedHashesMap["sha256"], gh.HexDigest)
+	}
+}
+
+func TestCheckpointUnsupported(t *testing.T) {
+	opts := CheckpointOptions{Path: filepath.Join(t.TempDir(), "checkpoint.json")}
+	_, err := HasherMultiFileCheckpoint(context.Background(), "nonexistentfile", opts, "sha256,blake3,sha3_256")
+	if err == nil || !strings.Contains(err.Error(), "blake3, sha3_256") {
+		t.Fatalf("Expected blake3 and sha3_256 to be refused, got %v", err)
+	}
+}
+
+func TestCheckpointMismatch(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "large")
+	if err := os.WriteFile(filePath, largeData(2*BufferSize), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+	opts := interruptedCheckpoint(t, filePath, "sha256")
+
+	if _, err := HasherMultiFileCheckpoint(context.Background(), filePath, opts, "md5"); err == nil {
+		t.Error("Expected an error for other algorithms")
+	}
+
+	// A copy with the same size and modification time is another file.
+	info, err := os.Stat(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	copyPath := filepath.Join(filepath.Dir(filePath), "copy")
+	if err := os.WriteFile(copyPath, largeData(2*BufferSize), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+	if err := os.Chtimes(copyPath, info.ModTime(), info.ModTime()); err != nil {
+		t.Fatalf("Failed to touch test file: %v", err)
+	}
+	if _, err := HasherMultiFileCheckpoint(context.Background(), copyPath, opts, "sha256"); err == nil {
+		t.Error("Expected an error for another file")
+	}
+
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(filePath, later, later); err != nil {
+		t.Fatalf("Failed to touch test file: %v", err)
+	}
+	if _, err := HasherMultiFileCheckpoint(context.Background(), filePath, opts, "sha256"); err == nil {
+		t.Error("Expected an error for a modified file")
+	}
+}
@@ -0,0 +1,200 @@
+package hash
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ChecksumFormat identifies the layout of a checksum file line.
+type ChecksumFormat int
+
+const (
+	// FormatGNU is the GNU coreutils layout: "<hex>  <file>" or, for binary
+	// mode, "<hex> *<file>".
+	FormatGNU ChecksumFormat = iota
+	// FormatBSD is the BSD tag layout: "SHA256 (<file>) = <hex>".
+	FormatBSD
+)
+
+// ErrInvalidChecksumLine is returned for lines that are not in a
+// recognized checksum format.
+var ErrInvalidChecksumLine = errors.New("improperly formatted checksum line")
+
+// ChecksumLine is a single entry of a checksum file as written by
+// sha256sum, md5sum and friends.
+type ChecksumLine struct {
+	// Algorithm is the tag of a BSD-style line and empty for GNU lines.
+	Algorithm string
+	// Digest is the expected digest in lowercase hex.
+	Digest string
+	// Path is the file the digest belongs to, with escapes resolved.
+	Path string
+	// Binary reports whether the GNU binary marker '*' was present.
+	Binary bool
+	Format ChecksumFormat
+}
+
+// digestAlgorithms maps digest sizes to the algorithm coreutils would use.
+var digestAlgorithms = map[int]string{
+	16: "md5",
+	20: "sha1",
+	28: "sha224",
+	32: "sha256",
+	48: "sha384",
+	64: "sha512",
+}
+
+// bsdTags maps algorithm names to the tags written by coreutils (sha3sum,
+// b2sum, cksum --tag) and the BSD md5/sha tools where they differ from the
+// upper-cased name.
+var bsdTags = map[string]string{
+	"sha512_224": "SHA512t224",
+	"sha512_256": "SHA512t256",
+	"sha3_224":   "SHA3-224",
+	"sha3_256":   "SHA3-256",
+	"sha3_384":   "SHA3-384",
+	"sha3_512":   "SHA3-512",
+	"blake2b256": "BLAKE2b-256",
+	"blake2b384": "BLAKE2b-384",
+	"blake2b512": "BLAKE2b",
+	"blake2s256": "BLAKE2s-256",
+	"ripemd160":  "RMD160",
+}
+
+// BSDTag returns the tag of a BSD-style line for the named algorithm, as
+// coreutils and the BSD tools write it, e.g. "SHA256", "SHA3-256" or
+// "BLAKE2b". Lookup accepts every tag it returns.
+func BSDTag(name string) string {
+	if tag, ok := bsdTags[name]; ok {
+		return tag
+	}
+
+	return strings.ToUpper(name)
+}
+
+// AlgorithmForDigest guesses the algorithm of a hex digest from its length,
+// matching the md5sum/sha*sum tool that produces digests of that size.
+func AlgorithmForDigest(hexDigest string) (string, error) {
+	name, ok := digestAlgorithms[len(hexDigest)/2]
+	if !ok || len(hexDigest)%2 != 0 {
+		return "", fmt.Errorf("cannot detect hash type from %d hex digits", len(hexDigest))
+	}
+
+	return name, nil
+}
+
+// ParseChecksumLine parses one line of a GNU or BSD checksum file. File
+// names escaped by coreutils (lines starting with a backslash) are
+// unescaped.
+func ParseChecksumLine(line string) (ChecksumLine, error) {
+	line = strings.TrimSuffix(line, "\r")
+
+	escaped := strings.HasPrefix(line, "\\")
+	if escaped {
+		line = line[1:]
+	}
+
+	cl, ok := parseBSDLine(line)
+	if !ok {
+		cl, ok = parseGNULine(line)
+	}
+	if !ok {
+		return ChecksumLine{}, ErrInvalidChecksumLine
+	}
+
+	if escaped {
+		path, ok := unescapeChecksumPath(cl.Path)
+		if !ok {
+			return ChecksumLine{}, ErrInvalidChecksumLine
+		}
+		cl.Path = path
+	}
+
+	return cl, nil
+}
+
+func parseBSDLine(line string) (ChecksumLine, bool) {
+	open := strings.Index(line, " (")
+	closing := strings.LastIndex(line, ") = ")
+	if open <= 0 || closing < open+2 {
+		return ChecksumLine{}, false
+	}
+
+	algo := line[:open]
+	path := line[open+2 : closing]
+	digest := line[closing+4:]
+
+	if strings.ContainsAny(algo, " \t") || path == "" || !isHex(digest) {
+		return ChecksumLine{}, false
+	}
+
+	return ChecksumLine{
+		Algorithm: algo,
+		Digest:    strings.ToLower(digest),
+		Path:      path,
+		Format:    FormatBSD,
+	}, true
+}
+
+func parseGNULine(line string) (ChecksumLine, bool) {
+	sep := strings.IndexByte(line, ' ')
+	if sep <= 0 || len(line) < sep+3 {
+		return ChecksumLine{}, false
+	}
+
+	digest := line[:sep]
+	marker := line[sep+1]
+	path := line[sep+2:]
+
+	if !isHex(digest) || (marker != ' ' && marker != '*') {
+		return ChecksumLine{}, false
+	}
+
+	return ChecksumLine{
+		Digest: strings.ToLower(digest),
+		Path:   path,
+		Binary: marker == '*',
+		Format: FormatGNU,
+	}, true
+}
+
+// unescapeChecksumPath resolves the "\\n", "\\r" and "\\\\" escapes
+// coreutils uses for file names containing newlines or backslashes.
+func unescapeChecksumPath(path string) (string, bool) {
+	var sb strings.Builder
+	for i := 0; i < len(path); i++ {
+		if path[i] != '\\' {
+			sb.WriteByte(path[i])
+			continue
+		}
+
+		i++
+		if i == len(path) {
+			return "", false
+		}
+
+		switch path[i] {
+		case '\\':
+			sb.WriteByte('\\')
+		case 'n':
+			sb.WriteByte('\n')
+		case 'r':
+			sb.WriteByte('\r')
+		default:
+			return "", false
+		}
+	}
+
+	return sb.String(), true
+}
+
+func isHex(s string) bool {
+	if s == "" || len(s)%2 != 0 {
+		return false
+	}
+
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
@@ -0,0 +1,138 @@
+package hash_test
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/TechMDW/hashit/pkg/hash"
+)
+
+func TestParseChecksumLine(t *testing.T) {
+	tests := []struct {
+		line     string
+		expected ChecksumLine
+	}{
+		{
+			line: "eb733a00c0c9d336e65691a37ab54293  file.txt",
+			expected: ChecksumLine{
+				Digest: "eb733a00c0c9d336e65691a37ab54293",
+				Path:   "file.txt",
+				Format: FormatGNU,
+			},
+		},
+		{
+			line: "EB733A00C0C9D336E65691A37AB54293 *dir/with space.bin",
+			expected: ChecksumLine{
+				Digest: "eb733a00c0c9d336e65691a37ab54293",
+				Path:   "dir/with space.bin",
+				Binary: true,
+				Format: FormatGNU,
+			},
+		},
+		{
+			line: "SHA256 (a (b).txt) = 916f0027a575074ce72a331777c3478d6513f786a591bd892da1a577bf2335f9",
+			expected: ChecksumLine{
+				Algorithm: "SHA256",
+				Digest:    "916f0027a575074ce72a331777c3478d6513f786a591bd892da1a577bf2335f9",
+				Path:      "a (b).txt",
+				Format:    FormatBSD,
+			},
+		},
+		{
+			line: "\\eb733a00c0c9d336e65691a37ab54293  new\\nline\\\\file",
+			expected: ChecksumLine{
+				Digest: "eb733a00c0c9d336e65691a37ab54293",
+				Path:   "new\nline\\file",
+				Format: FormatGNU,
+			},
+		},
+		{
+			line: "eb733a00c0c9d336e65691a37ab54293  crlf.txt\r",
+			expected: ChecksumLine{
+				Digest: "eb733a00c0c9d336e65691a37ab54293",
+				Path:   "crlf.txt",
+				Format: FormatGNU,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			cl, err := ParseChecksumLine(tt.line)
+			if err != nil {
+				t.Fatalf("ParseChecksumLine failed: %v", err)
+			}
+
+			if cl != tt.expected {
+				t.Errorf("Expected %+v, got %+v", tt.expected, cl)
+			}
+		})
+	}
+}
+
+func TestParseChecksumLineInvalid(t *testing.T) {
+	lines := []string{
+		"",
+		"eb733a00c0c9d336e65691a37ab54293",
+		"eb733a00c0c9d336e65691a37ab54293 file.txt",
+		"xyz  file.txt",
+		"abc  file.txt",
+		"SHA256 () = 916f0027",
+		"\\eb733a00c0c9d336e65691a37ab54293  bad\\escape",
+	}
+
+	for _, line := range lines {
+		if _, err := ParseChecksumLine(line); !errors.Is(err, ErrInvalidChecksumLine) {
+			t.Errorf("Expected ErrInvalidChecksumLine for %q, got %v", line, err)
+		}
+	}
+}
+
+func TestAlgorithmForDigest(t *testing.T) {
+	for hashType, expected := range map[string]string{
+		"md5":    expectedHashes.MD5,
+		"sha1":   expectedHashes.SHA1,
+		"sha224": expectedHashes.SHA2.SHA224,
+		"sha256": expectedHashes.SHA2.SHA256,
+		"sha384": expectedHashes.SHA2.SHA384,
+		"sha512": expectedHashes.SHA2.SHA512,
+	} {
+		algo, err := AlgorithmForDigest(expected)
+		if err != nil {
+			t.Fatalf("AlgorithmForDigest failed for %s: %v", hashType, err)
+		}
+		if algo != hashType {
+			t.Errorf("Expected %s, got %s", hashType, algo)
+		}
+	}
+
+	if _, err := AlgorithmForDigest(expectedHashes.Adler32); err == nil {
+		t.Error("Expected error for 8 hex digits, got nil")
+	}
+}
+
+func TestBSDTag(t *testing.T) {
+	tags := map[string]string{
+		"md5":        "MD5",
+		"sha256":     "SHA256",
+		"sha512_256": "SHA512t256",
+		"sha3_256":   "SHA3-256",
+		"blake2b512": "BLAKE2b",
+		"blake2b256": "BLAKE2b-256",
+		"ripemd160":  "RMD160",
+		"sm3":        "SM3",
+	}
+	for name, expected := range tags {
+		if got := BSDTag(name); got != expected {
+			t.Errorf("BSDTag(%q): expected %s, got %s", name, expected, got)
+		}
+	}
+
+	// check must understand every tag hashit writes.
+	for _, a := range Algorithms() {
+		algo, err := Lookup(BSDTag(a.Name))
+		if err != nil || algo.Name != a.Name {
+			t.Errorf("Lookup(%q): expected %s, got %v", BSDTag(a.Name), a.Name, err)
+		}
+	}
+}
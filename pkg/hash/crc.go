@@ -0,0 +1,57 @@
+package hash
+
+import (
+	"fmt"
+	"hash"
+	"strings"
+	"sync"
+
+	"github.com/TechMDW/hashit/pkg/hash/crc"
+)
+
+var (
+	crcMu         sync.Mutex
+	crcAlgorithms = map[string]*Algorithm{}
+)
+
+// lookupCRC returns an algorithm for a CRC of the reveng catalogue, named
+// like "CRC-16/XMODEM" or "crc16-xmodem", or for a custom CRC given in the
+// reveng notation, e.g. "width=16 poly=0x1021 init=0xffff". These are not
+// registered, so they are only computed when asked for by name.
+func lookupCRC(name string) (*Algorithm, error) {
+	p, err := crc.Lookup(name)
+	if strings.Contains(name, "=") {
+		p, err = crc.ParseParams(name)
+	} else if err != nil {
+		err = fmt.Errorf("unknown hash type: %s", name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	key := strings.ToLower(p.Name)
+	if key == "" {
+		key = p.String()
+	}
+
+	crcMu.Lock()
+	defer crcMu.Unlock()
+
+	if a, ok := crcAlgorithms[key]; ok {
+		return a, nil
+	}
+
+	a := &Algorithm{
+		Name:         key,
+		Aliases:      p.Aliases,
+		Family:       "crc",
+		Size:         p.Size(),
+		BlockSize:    1,
+		Security:     familySecurity["crc"].status,
+		SecurityNote: familySecurity["crc"].note,
+		New:          func() hash.Hash { return crc.New(p) },
+	}
+	crcAlgorithms[key] = a
+
+	return a, nil
+}
@@ -0,0 +1,120 @@
+package crc
+
+// Catalogue holds the CRCs of the reveng catalogue of parametrised CRC
+// algorithms (https://reveng.sourceforge.io/crc-catalogue/) up to 64 bits
+// wide, with their alternative names as aliases. CRC-82/DARC is the only
+// entry left out.
+var Catalogue = []Params{
+	{Name: "CRC-3/GSM", Width: 3, Poly: 0x3, Init: 0x0, XorOut: 0x7, Check: 0x4},
+	{Name: "CRC-3/ROHC", Width: 3, Poly: 0x3, Init: 0x7, RefIn: true, RefOut: true, XorOut: 0x0, Check: 0x6},
+	{Name: "CRC-4/G-704", Aliases: []string{"CRC-4/ITU"}, Width: 4, Poly: 0x3, Init: 0x0, RefIn: true, RefOut: true, XorOut: 0x0, Check: 0x7},
+	{Name: "CRC-4/INTERLAKEN", Width: 4, Poly: 0x3, Init: 0xf, XorOut: 0xf, Check: 0xb},
+	{Name: "CRC-5/EPC-C1G2", Aliases: []string{"CRC-5/EPC"}, Width: 5, Poly: 0x09, Init: 0x09, XorOut: 0x00, Check: 0x00},
+	{Name: "CRC-5/G-704", Aliases: []string{"CRC-5/ITU"}, Width: 5, Poly: 0x15, Init: 0x00, RefIn: true, RefOut: true, XorOut: 0x00, Check: 0x07},
+	{Name: "CRC-5/USB", Width: 5, Poly: 0x05, Init: 0x1f, RefIn: true, RefOut: true, XorOut: 0x1f, Check: 0x19},
+	{Name: "CRC-6/CDMA2000-A", Width: 6, Poly: 0x27, Init: 0x3f, XorOut: 0x00, Check: 0x0d},
+	{Name: "CRC-6/CDMA2000-B", Width: 6, Poly: 0x07, Init: 0x3f, XorOut: 0x00, Check: 0x3b},
+	{Name: "CRC-6/DARC", Width: 6, Poly: 0x19, Init: 0x00, RefIn: true, RefOut: true, XorOut: 0x00, Check: 0x26},
+	{Name: "CRC-6/G-704", Aliases: []string{"CRC-6/ITU"}, Width: 6, Poly: 0x03, Init: 0x00, RefIn: true, RefOut: true, XorOut: 0x00, Check: 0x06},
+	{Name: "CRC-6/GSM", Width: 6, Poly: 0x2f, Init: 0x00, XorOut: 0x3f, Check: 0x13},
+	{Name: "CRC-7/MMC", Aliases: []string{"CRC-7"}, Width: 7, Poly: 0x09, Init: 0x00, XorOut: 0x00, Check: 0x75},
+	{Name: "CRC-7/ROHC", Width: 7, Poly: 0x4f, Init: 0x7f, RefIn: true, RefOut: true, XorOut: 0x00, Check: 0x53},
+	{Name: "CRC-7/UMTS", Width: 7, Poly: 0x45, Init: 0x00, XorOut: 0x00, Check: 0x61},
+	{Name: "CRC-8/AUTOSAR", Width: 8, Poly: 0x2f, Init: 0xff, XorOut: 0xff, Check: 0xdf},
+	{Name: "CRC-8/BLUETOOTH", Width: 8, Poly: 0xa7, Init: 0x00, RefIn: true, RefOut: true, XorOut: 0x00, Check: 0x26},
+	{Name: "CRC-8/CDMA2000", Width: 8, Poly: 0x9b, Init: 0xff, XorOut: 0x00, Check: 0xda},
+	{Name: "CRC-8/DARC", Width: 8, Poly: 0x39, Init: 0x00, RefIn: true, RefOut: true, XorOut: 0x00, Check: 0x15},
+	{Name: "CRC-8/DVB-S2", Width: 8, Poly: 0xd5, Init: 0x00, XorOut: 0x00, Check: 0xbc},
+	{Name: "CRC-8/GSM-A", Width: 8, Poly: 0x1d, Init: 0x00, XorOut: 0x00, Check: 0x37},
+	{Name: "CRC-8/GSM-B", Width: 8, Poly: 0x49, Init: 0x00, XorOut: 0xff, Check: 0x94},
+	{Name: "CRC-8/HITAG", Width: 8, Poly: 0x1d, Init: 0xff, XorOut: 0x00, Check: 0xb4},
+	{Name: "CRC-8/I-432-1", Aliases: []string{"CRC-8/ITU"}, Width: 8, Poly: 0x07, Init: 0x00, XorOut: 0x55, Check: 0xa1},
+	{Name: "CRC-8/I-CODE", Width: 8, Poly: 0x1d, Init: 0xfd, XorOut: 0x00, Check: 0x7e},
+	{Name: "CRC-8/LTE", Width: 8, Poly: 0x9b, Init: 0x00, XorOut: 0x00, Check: 0xea},
+	{Name: "CRC-8/MAXIM-DOW", Aliases: []string{"CRC-8/MAXIM", "DOW-CRC"}, Width: 8, Poly: 0x31, Init: 0x00, RefIn: true, RefOut: true, XorOut: 0x00, Check: 0xa1},
+	{Name: "CRC-8/MIFARE-MAD", Width: 8, Poly: 0x1d, Init: 0xc7, XorOut: 0x00, Check: 0x99},
+	{Name: "CRC-8/NRSC-5", Width: 8, Poly: 0x31, Init: 0xff, XorOut: 0x00, Check: 0xf7},
+	{Name: "CRC-8/OPENSAFETY", Width: 8, Poly: 0x2f, Init: 0x00, XorOut: 0x00, Check: 0x3e},
+	{Name: "CRC-8/ROHC", Width: 8, Poly: 0x07, Init: 0xff, RefIn: true, RefOut: true, XorOut: 0x00, Check: 0xd0},
+	{Name: "CRC-8/SAE-J1850", Width: 8, Poly: 0x1d, Init: 0xff, XorOut: 0xff, Check: 0x4b},
+	{Name: "CRC-8/SMBUS", Aliases: []string{"CRC-8"}, Width: 8, Poly: 0x07, Init: 0x00, XorOut: 0x00, Check: 0xf4},
+	{Name: "CRC-8/TECH-3250", Aliases: []string{"CRC-8/AES", "CRC-8/EBU"}, Width: 8, Poly: 0x1d, Init: 0xff, RefIn: true, RefOut: true, XorOut: 0x00, Check: 0x97},
+	{Name: "CRC-8/WCDMA", Width: 8, Poly: 0x9b, Init: 0x00, RefIn: true, RefOut: true, XorOut: 0x00, Check: 0x25},
+	{Name: "CRC-10/ATM", Aliases: []string{"CRC-10", "CRC-10/I-610"}, Width: 10, Poly: 0x233, Init: 0x000, XorOut: 0x000, Check: 0x199},
+	{Name: "CRC-10/CDMA2000", Width: 10, Poly: 0x3d9, Init: 0x3ff, XorOut: 0x000, Check: 0x233},
+	{Name: "CRC-10/GSM", Width: 10, Poly: 0x175, Init: 0x000, XorOut: 0x3ff, Check: 0x12a},
+	{Name: "CRC-11/FLEXRAY", Aliases: []string{"CRC-11"}, Width: 11, Poly: 0x385, Init: 0x01a, XorOut: 0x000, Check: 0x5a3},
+	{Name: "CRC-11/UMTS", Width: 11, Poly: 0x307, Init: 0x000, XorOut: 0x000, Check: 0x061},
+	{Name: "CRC-12/CDMA2000", Width: 12, Poly: 0xf13, Init: 0xfff, XorOut: 0x000, Check: 0xd4d},
+	{Name: "CRC-12/DECT", Aliases: []string{"X-CRC-12"}, Width: 12, Poly: 0x80f, Init: 0x000, XorOut: 0x000, Check: 0xf5b},
+	{Name: "CRC-12/GSM", Width: 12, Poly: 0xd31, Init: 0x000, XorOut: 0xfff, Check: 0xb34},
+	{Name: "CRC-12/UMTS", Aliases: []string{"CRC-12/3GPP"}, Width: 12, Poly: 0x80f, Init: 0x000, RefOut: true, XorOut: 0x000, Check: 0xdaf},
+	{Name: "CRC-13/BBC", Width: 13, Poly: 0x1cf5, Init: 0x0000, XorOut: 0x0000, Check: 0x04fa},
+	{Name: "CRC-14/DARC", Width: 14, Poly: 0x0805, Init: 0x0000, RefIn: true, RefOut: true, XorOut: 0x0000, Check: 0x082d},
+	{Name: "CRC-14/GSM", Width: 14, Poly: 0x202d, Init: 0x0000, XorOut: 0x3fff, Check: 0x30ae},
+	{Name: "CRC-15/CAN", Aliases: []string{"CRC-15"}, Width: 15, Poly: 0x4599, Init: 0x0000, XorOut: 0x0000, Check: 0x059e},
+	{Name: "CRC-15/MPT1327", Width: 15, Poly: 0x6815, Init: 0x0000, XorOut: 0x0001, Check: 0x2566},
+	{Name: "CRC-16/ARC", Aliases: []string{"ARC", "CRC-16", "CRC-16/LHA", "CRC-IBM"}, Width: 16, Poly: 0x8005, Init: 0x0000, RefIn: true, RefOut: true, XorOut: 0x0000, Check: 0xbb3d},
+	{Name: "CRC-16/CDMA2000", Width: 16, Poly: 0xc867, Init: 0xffff, XorOut: 0x0000, Check: 0x4c06},
+	{Name: "CRC-16/CMS", Width: 16, Poly: 0x8005, Init: 0xffff, XorOut: 0x0000, Check: 0xaee7},
+	{Name: "CRC-16/DDS-110", Width: 16, Poly: 0x8005, Init: 0x800d, XorOut: 0x0000, Check: 0x9ecf},
+	{Name: "CRC-16/DECT-R", Aliases: []string{"R-CRC-16"}, Width: 16, Poly: 0x0589, Init: 0x0000, XorOut: 0x0001, Check: 0x007e},
+	{Name: "CRC-16/DECT-X", Aliases: []string{"X-CRC-16"}, Width: 16, Poly: 0x0589, Init: 0x0000, XorOut: 0x0000, Check: 0x007f},
+	{Name: "CRC-16/DNP", Width: 16, Poly: 0x3d65, Init: 0x0000, RefIn: true, RefOut: true, XorOut: 0xffff, Check: 0xea82},
+	{Name: "CRC-16/EN-13757", Width: 16, Poly: 0x3d65, Init: 0x0000, XorOut: 0xffff, Check: 0xc2b7},
+	{Name: "CRC-16/GENIBUS", Aliases: []string{"CRC-16/DARC", "CRC-16/EPC", "CRC-16/EPC-C1G2", "CRC-16/I-CODE"}, Width: 16, Poly: 0x1021, Init: 0xffff, XorOut: 0xffff, Check: 0xd64e},
+	{Name: "CRC-16/GSM", Width: 16, Poly: 0x1021, Init: 0x0000, XorOut: 0xffff, Check: 0xce3c},
+	{Name: "CRC-16/IBM-3740", Aliases: []string{"CRC-16/AUTOSAR", "CRC-16/CCITT-FALSE"}, Width: 16, Poly: 0x1021, Init: 0xffff, XorOut: 0x0000, Check: 0x29b1},
+	{Name: "CRC-16/IBM-SDLC", Aliases: []string{"CRC-16/ISO-HDLC", "CRC-16/ISO-IEC-14443-3-B", "CRC-16/X-25", "CRC-B", "X-25"}, Width: 16, Poly: 0x1021, Init: 0xffff, RefIn: true, RefOut: true, XorOut: 0xffff, Check: 0x906e},
+	{Name: "CRC-16/ISO-IEC-14443-3-A", Aliases: []string{"CRC-A"}, Width: 16, Poly: 0x1021, Init: 0xc6c6, RefIn: true, RefOut: true, XorOut: 0x0000, Check: 0xbf05},
+	{Name: "CRC-16/KERMIT", Aliases: []string{"CRC-16/BLUETOOTH", "CRC-16/CCITT", "CRC-16/CCITT-TRUE", "CRC-16/V-41-LSB", "CRC-CCITT", "KERMIT"}, Width: 16, Poly: 0x1021, Init: 0x0000, RefIn: true, RefOut: true, XorOut: 0x0000, Check: 0x2189},
+	{Name: "CRC-16/LJ1200", Width: 16, Poly: 0x6f63, Init: 0x0000, XorOut: 0x0000, Check: 0xbdf4},
+	{Name: "CRC-16/M17", Width: 16, Poly: 0x5935, Init: 0xffff, XorOut: 0x0000, Check: 0x772b},
+	{Name: "CRC-16/MAXIM-DOW", Aliases: []string{"CRC-16/MAXIM"}, Width: 16, Poly: 0x8005, Init: 0x0000, RefIn: true, RefOut: true, XorOut: 0xffff, Check: 0x44c2},
+	{Name: "CRC-16/MCRF4XX", Width: 16, Poly: 0x1021, Init: 0xffff, RefIn: true, RefOut: true, XorOut: 0x0000, Check: 0x6f91},
+	{Name: "CRC-16/MODBUS", Aliases: []string{"MODBUS"}, Width: 16, Poly: 0x8005, Init: 0xffff, RefIn: true, RefOut: true, XorOut: 0x0000, Check: 0x4b37},
+	{Name: "CRC-16/NRSC-5", Width: 16, Poly: 0x080b, Init: 0xffff, RefIn: true, RefOut: true, XorOut: 0x0000, Check: 0xa066},
+	{Name: "CRC-16/OPENSAFETY-A", Width: 16, Poly: 0x5935, Init: 0x0000, XorOut: 0x0000, Check: 0x5d38},
+	{Name: "CRC-16/OPENSAFETY-B", Width: 16, Poly: 0x755b, Init: 0x0000, XorOut: 0x0000, Check: 0x20fe},
+	{Name: "CRC-16/PROFIBUS", Aliases: []string{"CRC-16/IEC-61158-2"}, Width: 16, Poly: 0x1dcf, Init: 0xffff, XorOut: 0xffff, Check: 0xa819},
+	{Name: "CRC-16/RIELLO", Width: 16, Poly: 0x1021, Init: 0xb2aa, RefIn: true, RefOut: true, XorOut: 0x0000, Check: 0x63d0},
+	{Name: "CRC-16/SPI-FUJITSU", Aliases: []string{"CRC-16/AUG-CCITT"}, Width: 16, Poly: 0x1021, Init: 0x1d0f, XorOut: 0x0000, Check: 0xe5cc},
+	{Name: "CRC-16/T10-DIF", Width: 16, Poly: 0x8bb7, Init: 0x0000, XorOut: 0x0000, Check: 0xd0db},
+	{Name: "CRC-16/TELEDISK", Width: 16, Poly: 0xa097, Init: 0x0000, XorOut: 0x0000, Check: 0x0fb3},
+	{Name: "CRC-16/TMS37157", Width: 16, Poly: 0x1021, Init: 0x89ec, RefIn: true, RefOut: true, XorOut: 0x0000, Check: 0x26b1},
+	{Name: "CRC-16/UMTS", Aliases: []string{"CRC-16/BUYPASS", "CRC-16/VERIFONE"}, Width: 16, Poly: 0x8005, Init: 0x0000, XorOut: 0x0000, Check: 0xfee8},
+	{Name: "CRC-16/USB", Width: 16, Poly: 0x8005, Init: 0xffff, RefIn: true, RefOut: true, XorOut: 0xffff, Check: 0xb4c8},
+	{Name: "CRC-16/XMODEM", Aliases: []string{"CRC-16/ACORN", "CRC-16/LTE", "CRC-16/V-41-MSB", "XMODEM", "ZMODEM"}, Width: 16, Poly: 0x1021, Init: 0x0000, XorOut: 0x0000, Check: 0x31c3},
+	{Name: "CRC-17/CAN-FD", Width: 17, Poly: 0x1685b, Init: 0x00000, XorOut: 0x00000, Check: 0x04f03},
+	{Name: "CRC-21/CAN-FD", Width: 21, Poly: 0x102899, Init: 0x000000, XorOut: 0x000000, Check: 0x0ed841},
+	{Name: "CRC-24/BLE", Width: 24, Poly: 0x00065b, Init: 0x555555, RefIn: true, RefOut: true, XorOut: 0x000000, Check: 0xc25a56},
+	{Name: "CRC-24/FLEXRAY-A", Width: 24, Poly: 0x5d6dcb, Init: 0xfedcba, XorOut: 0x000000, Check: 0x7979bd},
+	{Name: "CRC-24/FLEXRAY-B", Width: 24, Poly: 0x5d6dcb, Init: 0xabcdef, XorOut: 0x000000, Check: 0x1f23b8},
+	{Name: "CRC-24/INTERLAKEN", Width: 24, Poly: 0x328b63, Init: 0xffffff, XorOut: 0xffffff, Check: 0xb4f3e6},
+	{Name: "CRC-24/LTE-A", Width: 24, Poly: 0x864cfb, Init: 0x000000, XorOut: 0x000000, Check: 0xcde703},
+	{Name: "CRC-24/LTE-B", Width: 24, Poly: 0x800063, Init: 0x000000, XorOut: 0x000000, Check: 0x23ef52},
+	{Name: "CRC-24/OPENPGP", Aliases: []string{"CRC-24"}, Width: 24, Poly: 0x864cfb, Init: 0xb704ce, XorOut: 0x000000, Check: 0x21cf02},
+	{Name: "CRC-24/OS-9", Width: 24, Poly: 0x800063, Init: 0xffffff, XorOut: 0xffffff, Check: 0x200fa5},
+	{Name: "CRC-30/CDMA", Width: 30, Poly: 0x2030b9c7, Init: 0x3fffffff, XorOut: 0x3fffffff, Check: 0x04c34abf},
+	{Name: "CRC-31/PHILIPS", Width: 31, Poly: 0x04c11db7, Init: 0x7fffffff, XorOut: 0x7fffffff, Check: 0x0ce9e46c},
+	{Name: "CRC-32/AIXM", Aliases: []string{"CRC-32Q"}, Width: 32, Poly: 0x814141ab, Init: 0x00000000, XorOut: 0x00000000, Check: 0x3010bf7f},
+	{Name: "CRC-32/AUTOSAR", Width: 32, Poly: 0xf4acfb13, Init: 0xffffffff, RefIn: true, RefOut: true, XorOut: 0xffffffff, Check: 0x1697d06a},
+	{Name: "CRC-32/BASE91-D", Aliases: []string{"CRC-32D"}, Width: 32, Poly: 0xa833982b, Init: 0xffffffff, RefIn: true, RefOut: true, XorOut: 0xffffffff, Check: 0x87315576},
+	{Name: "CRC-32/BZIP2", Aliases: []string{"CRC-32/AAL5", "CRC-32/DECT-B", "B-CRC-32"}, Width: 32, Poly: 0x04c11db7, Init: 0xffffffff, XorOut: 0xffffffff, Check: 0xfc891918},
+	{Name: "CRC-32/CD-ROM-EDC", Width: 32, Poly: 0x8001801b, Init: 0x00000000, RefIn: true, RefOut: true, XorOut: 0x00000000, Check: 0x6ec2edc4},
+	{Name: "CRC-32/CKSUM", Aliases: []string{"CKSUM", "CRC-32/POSIX"}, Width: 32, Poly: 0x04c11db7, Init: 0x00000000, XorOut: 0xffffffff, Check: 0x765e7680},
+	{Name: "CRC-32/ISCSI", Aliases: []string{"CRC-32/BASE91-C", "CRC-32/CASTAGNOLI", "CRC-32/INTERLAKEN", "CRC-32C", "CRC-32/NVME"}, Width: 32, Poly: 0x1edc6f41, Init: 0xffffffff, RefIn: true, RefOut: true, XorOut: 0xffffffff, Check: 0xe3069283},
+	{Name: "CRC-32/ISO-HDLC", Aliases: []string{"CRC-32", "CRC-32/ADCCP", "CRC-32/V-42", "CRC-32/XZ", "PKZIP"}, Width: 32, Poly: 0x04c11db7, Init: 0xffffffff, RefIn: true, RefOut: true, XorOut: 0xffffffff, Check: 0xcbf43926},
+	{Name: "CRC-32/JAMCRC", Aliases: []string{"JAMCRC"}, Width: 32, Poly: 0x04c11db7, Init: 0xffffffff, RefIn: true, RefOut: true, XorOut: 0x00000000, Check: 0x340bc6d9},
+	{Name: "CRC-32/MEF", Width: 32, Poly: 0x741b8cd7, Init: 0xffffffff, RefIn: true, RefOut: true, XorOut: 0x00000000, Check: 0xd2c22f51},
+	{Name: "CRC-32/MPEG-2", Width: 32, Poly: 0x04c11db7, Init: 0xffffffff, XorOut: 0x00000000, Check: 0x0376e6e7},
+	{Name: "CRC-32/XFER", Aliases: []string{"XFER"}, Width: 32, Poly: 0x000000af, Init: 0x00000000, XorOut: 0x00000000, Check: 0xbd0be338},
+	{Name: "CRC-40/GSM", Width: 40, Poly: 0x0004820009, Init: 0x0000000000, XorOut: 0xffffffffff, Check: 0xd4164fc646},
+	{Name: "CRC-64/ECMA-182", Aliases: []string{"CRC-64"}, Width: 64, Poly: 0x42f0e1eba9ea3693, Init: 0x0000000000000000, XorOut: 0x0000000000000000, Check: 0x6c40df5f0b497347},
+	{Name: "CRC-64/GO-ISO", Width: 64, Poly: 0x000000000000001b, Init: 0xffffffffffffffff, RefIn: true, RefOut: true, XorOut: 0xffffffffffffffff, Check: 0xb90956c775a41001},
+	{Name: "CRC-64/MS", Width: 64, Poly: 0x259c84cba6426349, Init: 0xffffffffffffffff, RefIn: true, RefOut: true, XorOut: 0x0000000000000000, Check: 0x75d4b74f024eceea},
+	{Name: "CRC-64/NVME", Width: 64, Poly: 0xad93d23594c93659, Init: 0xffffffffffffffff, RefIn: true, RefOut: true, XorOut: 0xffffffffffffffff, Check: 0xae8b14860a799888},
+	{Name: "CRC-64/REDIS", Width: 64, Poly: 0xad93d23594c935a9, Init: 0x0000000000000000, RefIn: true, RefOut: true, XorOut: 0x0000000000000000, Check: 0xe9c6d914c4b8d9ca},
+	{Name: "CRC-64/WE", Width: 64, Poly: 0x42f0e1eba9ea3693, Init: 0xffffffffffffffff, XorOut: 0xffffffffffffffff, Check: 0x62ec59e3f1a4f00a},
+	{Name: "CRC-64/XZ", Aliases: []string{"CRC-64/GO-ECMA"}, Width: 64, Poly: 0x42f0e1eba9ea3693, Init: 0xffffffffffffffff, RefIn: true, RefOut: true, XorOut: 0xffffffffffffffff, Check: 0x995dc9bbdf1939fa},
+}
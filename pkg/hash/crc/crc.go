@@ -0,0 +1,309 @@
+// Package crc implements cyclic redundancy checks of any width from 1 to 64
+// bits described by the Rocksoft model: width, poly, init, refin, refout and
+// xorout, as used by the reveng CRC catalogue.
+//
+// Catalogue holds every catalogue entry of up to 64 bits by name, and
+// ParseParams reads custom models written in the reveng notation:
+//
+//	width=16 poly=0x1021 init=0xffff refin=false refout=false xorout=0x0000
+package crc
+
+import (
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// Params describes a CRC in the Rocksoft model.
+type Params struct {
+	// Name is the catalogue name, e.g. "CRC-16/XMODEM". It is empty for
+	// custom models.
+	Name    string
+	Aliases []string
+	// Width is the register size in bits, from 1 to 64.
+	Width uint
+	// Poly is the generator polynomial without its top bit, unreflected.
+	Poly uint64
+	// Init is the initial register value, unreflected.
+	Init uint64
+	// RefIn reports whether input bytes are processed least significant
+	// bit first.
+	RefIn bool
+	// RefOut reports whether the register is reflected before XorOut.
+	RefOut bool
+	// XorOut is XORed into the register to give the final value.
+	XorOut uint64
+	// Check is the CRC of the ASCII string "123456789". It is verified by
+	// ParseParams when given.
+	Check uint64
+}
+
+// mask returns the bits of a Width wide register.
+func (p *Params) mask() uint64 {
+	return ^uint64(0) >> (64 - p.Width)
+}
+
+// Validate reports whether the parameters describe a CRC supported by this
+// package.
+func (p *Params) Validate() error {
+	if p.Width < 1 || p.Width > 64 {
+		return fmt.Errorf("crc width %d is not between 1 and 64", p.Width)
+	}
+
+	m := p.mask()
+	if p.Poly&^m != 0 || p.Init&^m != 0 || p.XorOut&^m != 0 || p.Check&^m != 0 {
+		return fmt.Errorf("crc parameters are wider than %d bits", p.Width)
+	}
+	if p.Poly&1 == 0 {
+		return fmt.Errorf("crc polynomial %#x has no constant term", p.Poly)
+	}
+
+	return nil
+}
+
+// String returns the parameters in the reveng notation.
+func (p Params) String() string {
+	digits := int(p.Width+3) / 4
+	s := fmt.Sprintf("width=%d poly=0x%0*x init=0x%0*x refin=%t refout=%t xorout=0x%0*x check=0x%0*x",
+		p.Width, digits, p.Poly, digits, p.Init, p.RefIn, p.RefOut, digits, p.XorOut, digits, p.Check)
+	if p.Name != "" {
+		s += fmt.Sprintf(" name=%q", p.Name)
+	}
+
+	return s
+}
+
+// Size returns the length of the big-endian digest in bytes.
+func (p *Params) Size() int {
+	return int(p.Width+7) / 8
+}
+
+// ParseParams parses a CRC model in the reveng notation. width and poly are
+// required; init, xorout, refin and refout default to zero and false. refin
+// and refout also accept true/false spelled as 1/0 or t/f. When check is
+// given the model is verified against it. When the parameters equal an
+// entry of the catalogue, that entry is returned.
+func ParseParams(s string) (Params, error) {
+	var p Params
+	var check *uint64
+	seen := map[string]bool{}
+
+	for _, field := range strings.Fields(s) {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			return Params{}, fmt.Errorf("invalid crc parameter %q", field)
+		}
+		key = strings.ToLower(key)
+		if seen[key] {
+			return Params{}, fmt.Errorf("duplicate crc parameter %q", key)
+		}
+		seen[key] = true
+
+		var err error
+		switch key {
+		case "width":
+			var w uint64
+			w, err = strconv.ParseUint(value, 0, 8)
+			p.Width = uint(w)
+		case "poly":
+			p.Poly, err = strconv.ParseUint(value, 0, 64)
+		case "init":
+			p.Init, err = strconv.ParseUint(value, 0, 64)
+		case "xorout":
+			p.XorOut, err = strconv.ParseUint(value, 0, 64)
+		case "refin":
+			p.RefIn, err = strconv.ParseBool(value)
+		case "refout":
+			p.RefOut, err = strconv.ParseBool(value)
+		case "check":
+			var c uint64
+			c, err = strconv.ParseUint(value, 0, 64)
+			check = &c
+		case "name":
+			p.Name = strings.Trim(value, `"`)
+		case "residue":
+			// The residue follows from the other parameters.
+			_, err = strconv.ParseUint(value, 0, 64)
+		default:
+			return Params{}, fmt.Errorf("unknown crc parameter %q", key)
+		}
+		if err != nil {
+			return Params{}, fmt.Errorf("invalid crc parameter %q: %w", field, err)
+		}
+	}
+
+	if !seen["width"] || !seen["poly"] {
+		return Params{}, fmt.Errorf("crc parameters need at least width and poly")
+	}
+	if err := p.Validate(); err != nil {
+		return Params{}, err
+	}
+
+	computed := Checksum(p, []byte("123456789"))
+	if check != nil && *check != computed {
+		return Params{}, fmt.Errorf("crc check value %#x does not match computed %#x", *check, computed)
+	}
+	p.Check = computed
+
+	for _, c := range Catalogue {
+		if c.Width == p.Width && c.Poly == p.Poly && c.Init == p.Init && c.RefIn == p.RefIn && c.RefOut == p.RefOut && c.XorOut == p.XorOut {
+			return c, nil
+		}
+	}
+
+	return p, nil
+}
+
+// Lookup returns the catalogue entry with the given name or alias. Names
+// are matched case-insensitively and ignoring "-", "/", "_" and spaces, so
+// "crc16-xmodem" finds CRC-16/XMODEM.
+func Lookup(name string) (Params, error) {
+	if p, ok := catalogueNames[normalizeName(name)]; ok {
+		return *p, nil
+	}
+
+	return Params{}, fmt.Errorf("unknown crc: %s", name)
+}
+
+var catalogueNames = func() map[string]*Params {
+	m := map[string]*Params{}
+	for i := range Catalogue {
+		p := &Catalogue[i]
+		for _, name := range append([]string{p.Name}, p.Aliases...) {
+			key := normalizeName(name)
+			if _, ok := m[key]; ok {
+				panic("crc: duplicate catalogue name " + name)
+			}
+			m[key] = p
+		}
+	}
+	return m
+}()
+
+func normalizeName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '-', '/', '_', ' ':
+			return -1
+		}
+		return r
+	}, strings.ToLower(name))
+}
+
+// Hasher computes a CRC. It implements hash.Hash and hash.Hash64; Sum
+// appends the CRC big-endian in Size bytes.
+type Hasher struct {
+	p     Params
+	table [256]uint64
+	// reg is the register, reflected when p.RefIn is set and otherwise
+	// aligned to the top bits.
+	reg uint64
+}
+
+// New returns a Hasher for the CRC described by p, which must be valid.
+func New(p Params) *Hasher {
+	h := &Hasher{p: p}
+
+	if p.RefIn {
+		poly := reflect(p.Poly, p.Width)
+		for i := range h.table {
+			r := uint64(i)
+			for j := 0; j < 8; j++ {
+				if r&1 != 0 {
+					r = r>>1 ^ poly
+				} else {
+					r >>= 1
+				}
+			}
+			h.table[i] = r
+		}
+	} else {
+		poly := p.Poly << (64 - p.Width)
+		for i := range h.table {
+			r := uint64(i) << 56
+			for j := 0; j < 8; j++ {
+				if r&(1<<63) != 0 {
+					r = r<<1 ^ poly
+				} else {
+					r <<= 1
+				}
+			}
+			h.table[i] = r
+		}
+	}
+
+	h.Reset()
+	return h
+}
+
+// Checksum returns the CRC of data described by p.
+func Checksum(p Params, data []byte) uint64 {
+	h := New(p)
+	h.Write(data)
+	return h.Sum64()
+}
+
+// Params returns the parameters of the CRC.
+func (h *Hasher) Params() Params {
+	return h.p
+}
+
+func (h *Hasher) Reset() {
+	if h.p.RefIn {
+		h.reg = reflect(h.p.Init, h.p.Width)
+	} else {
+		h.reg = h.p.Init << (64 - h.p.Width)
+	}
+}
+
+func (h *Hasher) Write(p []byte) (int, error) {
+	reg := h.reg
+	if h.p.RefIn {
+		for _, b := range p {
+			reg = h.table[byte(reg)^b] ^ reg>>8
+		}
+	} else {
+		for _, b := range p {
+			reg = h.table[byte(reg>>56)^b] ^ reg<<8
+		}
+	}
+	h.reg = reg
+
+	return len(p), nil
+}
+
+// Sum64 returns the CRC of the data written so far.
+func (h *Hasher) Sum64() uint64 {
+	crc := h.reg
+	if !h.p.RefIn {
+		crc >>= 64 - h.p.Width
+	}
+	if h.p.RefIn != h.p.RefOut {
+		crc = reflect(crc, h.p.Width)
+	}
+
+	return (crc ^ h.p.XorOut) & h.p.mask()
+}
+
+func (h *Hasher) Sum(b []byte) []byte {
+	crc := h.Sum64()
+	for i := h.p.Size() - 1; i >= 0; i-- {
+		b = append(b, byte(crc>>(8*i)))
+	}
+
+	return b
+}
+
+func (h *Hasher) Size() int {
+	return h.p.Size()
+}
+
+func (h *Hasher) BlockSize() int {
+	return 1
+}
+
+// reflect reverses the low width bits of x.
+func reflect(x uint64, width uint) uint64 {
+	return bits.Reverse64(x) >> (64 - width)
+}
@@ -0,0 +1,126 @@
+package crc_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	. "github.com/TechMDW/hashit/pkg/hash/crc"
+)
+
+var checkInput = []byte("123456789")
+
+func TestCatalogueCheck(t *testing.T) {
+	for _, p := range Catalogue {
+		if err := p.Validate(); err != nil {
+			t.Errorf("%s: %v", p.Name, err)
+			continue
+		}
+		if got := Checksum(p, checkInput); got != p.Check {
+			t.Errorf("%s: check expected %#x, got %#x", p.Name, p.Check, got)
+		}
+	}
+}
+
+func TestStreaming(t *testing.T) {
+	for _, p := range Catalogue {
+		h := New(p)
+		for i := range checkInput {
+			h.Write(checkInput[i : i+1])
+		}
+		if got := h.Sum64(); got != p.Check {
+			t.Errorf("%s: bytewise check expected %#x, got %#x", p.Name, p.Check, got)
+		}
+
+		h.Reset()
+		h.Write(checkInput)
+		if got := h.Sum64(); got != p.Check {
+			t.Errorf("%s: check after Reset expected %#x, got %#x", p.Name, p.Check, got)
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	tests := map[string]string{
+		"CRC-16/XMODEM":      "CRC-16/XMODEM",
+		"crc16-xmodem":       "CRC-16/XMODEM",
+		"CRC-16/CCITT-FALSE": "CRC-16/IBM-3740",
+		"modbus":             "CRC-16/MODBUS",
+		"crc-24/openpgp":     "CRC-24/OPENPGP",
+		"CRC-32C":            "CRC-32/ISCSI",
+		"crc8":               "CRC-8/SMBUS",
+	}
+
+	for name, expected := range tests {
+		p, err := Lookup(name)
+		if err != nil {
+			t.Errorf("Lookup(%q) failed: %v", name, err)
+			continue
+		}
+		if p.Name != expected {
+			t.Errorf("Lookup(%q) = %s, expected %s", name, p.Name, expected)
+		}
+	}
+
+	if _, err := Lookup("CRC-82/DARC"); err == nil {
+		t.Error("Expected error for CRC-82/DARC, got nil")
+	}
+}
+
+func TestSum(t *testing.T) {
+	tests := map[string]string{
+		"CRC-3/GSM":      "04",
+		"CRC-12/UMTS":    "0daf",
+		"CRC-24/OPENPGP": "21cf02",
+		"CRC-40/GSM":     "d4164fc646",
+		"CRC-64/XZ":      "995dc9bbdf1939fa",
+	}
+
+	for name, expected := range tests {
+		p, _ := Lookup(name)
+		h := New(p)
+		h.Write(checkInput)
+		if got := hex.EncodeToString(h.Sum(nil)); got != expected || h.Size() != len(expected)/2 {
+			t.Errorf("%s: Sum expected %s, got %s (size %d)", name, expected, got, h.Size())
+		}
+	}
+}
+
+func TestParseParams(t *testing.T) {
+	p, err := ParseParams("width=16 poly=0x1021 init=0xffff refin=false refout=false xorout=0x0000 check=0x29b1")
+	if err != nil {
+		t.Fatalf("ParseParams failed: %v", err)
+	}
+	if p.Name != "CRC-16/IBM-3740" {
+		t.Errorf("Expected catalogue entry CRC-16/IBM-3740, got %q", p.Name)
+	}
+
+	p, err = ParseParams("width=16 poly=0x1021 init=0x1234")
+	if err != nil {
+		t.Fatalf("ParseParams failed: %v", err)
+	}
+	if p.Name != "" || p.Check != Checksum(p, checkInput) {
+		t.Errorf("Unexpected custom parameters %v", p)
+	}
+
+	roundTrip, err := ParseParams(p.String())
+	if err != nil || roundTrip.String() != p.String() {
+		t.Errorf("ParseParams(%q) = %v, %v; expected %v", p.String(), roundTrip, err, p)
+	}
+
+	for _, s := range []string{
+		"",
+		"poly=0x1021",
+		"width=16",
+		"width=65 poly=0x1b",
+		"width=8 poly=0x107",
+		"width=8 poly=0x06",
+		"width=16 poly=0x1021 check=0x1234",
+		"width=16 poly=0x1021 refin=maybe",
+		"width=16 poly=0x1021 bogus=1",
+		"width=16 width=8 poly=0x07",
+	} {
+		if _, err := ParseParams(s); err == nil {
+			t.Errorf("ParseParams(%q): expected error, got nil", s)
+		}
+	}
+}
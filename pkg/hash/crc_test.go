@@ -0,0 +1,60 @@
+package hash_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/TechMDW/hashit/pkg/hash"
+)
+
+func TestComputeHashCRCCatalogue(t *testing.T) {
+	// The catalogue agrees with the CRCs of hash/crc32 and hash/crc64.
+	tests := map[string]string{
+		"CRC-32/ISO-HDLC": expectedHashesMap["crc32_ieee"],
+		"crc32c":          expectedHashesMap["crc32_castagnoli"],
+		"CRC-32/ISCSI":    expectedHashesMap["crc32_castagnoli"],
+		"crc-64/go-iso":   expectedHashesMap["crc64_iso"],
+		"CRC-64/XZ":       expectedHashesMap["crc64_ecma"],
+		"width=32 poly=0x741b8cd7 init=0xffffffff refin=true refout=true xorout=0xffffffff": expectedHashesMap["crc32_koopman"],
+	}
+
+	for name, expected := range tests {
+		gh, err := ComputeHash(context.Background(), []byte("test data"), name, false)
+		if err != nil {
+			t.Fatalf("ComputeHash(%q) failed: %v", name, err)
+		}
+		if gh.HexDigest != expected {
+			t.Errorf("%s: expected %s, got %s", name, expected, gh.HexDigest)
+		}
+	}
+
+	gh, err := ComputeHash(context.Background(), []byte("123456789"), "crc16-xmodem", false)
+	if err != nil {
+		t.Fatalf("ComputeHash failed: %v", err)
+	}
+	if gh.HexDigest != "31c3" || gh.Algorithm != "crc-16/xmodem" {
+		t.Errorf("Expected crc-16/xmodem 31c3, got %s %s", gh.Algorithm, gh.HexDigest)
+	}
+
+	if _, err := ComputeHash(context.Background(), []byte("test data"), "width=16 poly=0x1021 check=0x1234", false); err == nil {
+		t.Error("Expected error for a wrong check value, got nil")
+	}
+}
+
+func TestHasherMultiCRCCatalogue(t *testing.T) {
+	hashes, err := HasherMulti(context.Background(), []byte("123456789"), "sha256,CRC-24/OPENPGP,crc-24")
+	if err != nil {
+		t.Fatalf("HasherMulti failed: %v", err)
+	}
+
+	arr := hashes.Array()
+	if len(arr) != 2 || arr[1].Type != "crc-24/openpgp" || arr[1].Hash != "21cf02" {
+		t.Errorf("Unexpected digests %v", arr)
+	}
+
+	for _, a := range Algorithms() {
+		if a.Name == "crc-24/openpgp" {
+			t.Error("Catalogue CRCs must not be registered")
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package hash
+
+import (
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+)
+
+// Encoder renders digests as text.
+type Encoder interface {
+	// Name is the name the encoder is selected by with ParseEncoding.
+	Name() string
+	// Encode returns the text form of sum, a digest computed with the named
+	// algorithm.
+	Encode(algorithm string, sum []byte) (string, error)
+}
+
+type encoder struct {
+	name   string
+	encode func(algorithm string, sum []byte) (string, error)
+}
+
+func (e encoder) Name() string { return e.name }
+
+func (e encoder) Encode(algorithm string, sum []byte) (string, error) {
+	return e.encode(algorithm, sum)
+}
+
+func plainEncoder(name string, encode func(sum []byte) string) Encoder {
+	return encoder{name, func(_ string, sum []byte) (string, error) {
+		return encode(sum), nil
+	}}
+}
+
+// Built-in encoders.
+var (
+	// EncodingHex is lowercase hexadecimal, the default.
+	EncodingHex = plainEncoder("hex", hex.EncodeToString)
+	// EncodingHexUpper is uppercase hexadecimal.
+	EncodingHexUpper = plainEncoder("HEX", func(sum []byte) string {
+		return strings.ToUpper(hex.EncodeToString(sum))
+	})
+	// EncodingBase64 is padded standard base64 (RFC 4648 section 4).
+	EncodingBase64 = plainEncoder("base64", base64.StdEncoding.EncodeToString)
+	// EncodingBase64Raw is standard base64 without padding.
+	EncodingBase64Raw = plainEncoder("base64-raw", base64.RawStdEncoding.EncodeToString)
+	// EncodingBase64URL is padded URL-safe base64 (RFC 4648 section 5).
+	EncodingBase64URL = plainEncoder("base64url", base64.URLEncoding.EncodeToString)
+	// EncodingBase64URLRaw is URL-safe base64 without padding.
+	EncodingBase64URLRaw = plainEncoder("base64url-raw", base64.RawURLEncoding.EncodeToString)
+	// EncodingBase32 is padded standard base32 (RFC 4648 section 6).
+	EncodingBase32 = plainEncoder("base32", base32.StdEncoding.EncodeToString)
+	// EncodingBase58 uses the Bitcoin alphabet, as in multihash and IPFS.
+	EncodingBase58 = plainEncoder("base58", encodeBase58)
+	// EncodingNix32 is the base32 variant used for Nix store hashes.
+	EncodingNix32 = plainEncoder("nix32", encodeNix32)
+	// EncodingSRI is a Subresource Integrity string such as
+	// "sha384-<base64>". It is only defined for SHA-256, SHA-384 and
+	// SHA-512.
+	EncodingSRI Encoder = encoder{"sri", encodeSRI}
+)
+
+var (
+	encodersMu sync.RWMutex
+	encoders   = map[string]Encoder{}
+	// encoderNames keeps the registration order for Encodings.
+	encoderNames []string
+)
+
+func init() {
+	for _, e := range []Encoder{
+		EncodingHex, EncodingHexUpper,
+		EncodingBase64, EncodingBase64Raw, EncodingBase64URL, EncodingBase64URLRaw,
+		EncodingBase32, EncodingBase58, EncodingNix32, EncodingSRI,
+	} {
+		if err := RegisterEncoding(e); err != nil {
+			panic(err)
+		}
+	}
+	encoders["nixbase32"] = EncodingNix32
+}
+
+// RegisterEncoding adds an encoder that can be selected by name with
+// ParseEncoding.
+func RegisterEncoding(e Encoder) error {
+	encodersMu.Lock()
+	defer encodersMu.Unlock()
+
+	if _, ok := encoders[e.Name()]; ok {
+		return fmt.Errorf("encoding %q is already registered", e.Name())
+	}
+
+	encoders[e.Name()] = e
+	encoderNames = append(encoderNames, e.Name())
+	return nil
+}
+
+// ParseEncoding returns the encoder registered under name. Names are
+// matched exactly first, so "hex" and "HEX" differ, and then
+// case-insensitively.
+func ParseEncoding(name string) (Encoder, error) {
+	encodersMu.RLock()
+	defer encodersMu.RUnlock()
+
+	if e, ok := encoders[name]; ok {
+		return e, nil
+	}
+	if e, ok := encoders[strings.ToLower(name)]; ok {
+		return e, nil
+	}
+
+	return nil, fmt.Errorf("unknown encoding: %s", name)
+}
+
+// Encodings returns the names of all registered encoders.
+func Encodings() []string {
+	encodersMu.RLock()
+	defer encodersMu.RUnlock()
+
+	return append([]string(nil), encoderNames...)
+}
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+func encodeBase58(sum []byte) string {
+	n := new(big.Int).SetBytes(sum)
+	radix := big.NewInt(58)
+	mod := new(big.Int)
+
+	var out []byte
+	for n.Sign() > 0 {
+		n.DivMod(n, radix, mod)
+		out = append(out, base58Alphabet[mod.Int64()])
+	}
+	for _, b := range sum {
+		if b != 0 {
+			break
+		}
+		out = append(out, base58Alphabet[0])
+	}
+
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+
+	return string(out)
+}
+
+const nix32Alphabet = "0123456789abcdfghijklmnpqrsvwxyz"
+
+// encodeNix32 implements printHash32 from Nix: the digest is read as a
+// little-endian number and printed most significant 5-bit group first.
+func encodeNix32(sum []byte) string {
+	if len(sum) == 0 {
+		return ""
+	}
+	n := (len(sum)*8-1)/5 + 1
+
+	out := make([]byte, 0, n)
+	for i := n - 1; i >= 0; i-- {
+		b := i * 5
+		c := b / 8
+		j := b % 8
+
+		v := sum[c] >> j
+		if c+1 < len(sum) {
+			v |= sum[c+1] << (8 - j)
+		}
+		out = append(out, nix32Alphabet[v&0x1f])
+	}
+
+	return string(out)
+}
+
+func encodeSRI(algorithm string, sum []byte) (string, error) {
+	switch algorithm {
+	case "sha256", "sha384", "sha512":
+		return algorithm + "-" + base64.StdEncoding.EncodeToString(sum), nil
+	default:
+		return "", fmt.Errorf("sri encoding is only defined for sha256, sha384 and sha512, not %s", algorithm)
+	}
+}
@@ -0,0 +1,89 @@
+package hash_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"testing"
+
+	. "github.com/TechMDW/hashit/pkg/hash"
+)
+
+// Encodings of the SHA-256 digest of the empty string. The nix32 value is the
+// well-known Nix store hash of an empty file.
+var expectedEncodings = map[string]string{
+	"hex":           "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+	"HEX":           "E3B0C44298FC1C149AFBF4C8996FB92427AE41E4649B934CA495991B7852B855",
+	"base64":        "47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU=",
+	"base64-raw":    "47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU",
+	"base64url":     "47DEQpj8HBSa-_TImW-5JCeuQeRkm5NMpJWZG3hSuFU=",
+	"base64url-raw": "47DEQpj8HBSa-_TImW-5JCeuQeRkm5NMpJWZG3hSuFU",
+	"base32":        "4OYMIQUY7QOBJGX36TEJS35ZEQT24QPEMSNZGTFESWMRW6CSXBKQ====",
+	"base58":        "GKot5hBsd81kMupNCXHaqbhv3huEbxAFMLnpcX2hniwn",
+	"nix32":         "0mdqa9w1p6cmli6976v4wi0sw9r4p5prkj7lzfd1877wk11c9c73",
+	"sri":           "sha256-47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU=",
+}
+
+func TestEncodings(t *testing.T) {
+	sum := sha256.Sum256(nil)
+
+	for name, expected := range expectedEncodings {
+		enc, err := ParseEncoding(name)
+		if err != nil {
+			t.Fatalf("ParseEncoding(%q) failed: %v", name, err)
+		}
+		if enc.Name() != name {
+			t.Errorf("ParseEncoding(%q).Name() = %s", name, enc.Name())
+		}
+
+		digest, err := enc.Encode("sha256", sum[:])
+		if err != nil {
+			t.Fatalf("%s: Encode failed: %v", name, err)
+		}
+		if digest != expected {
+			t.Errorf("%s: expected %s, got %s", name, expected, digest)
+		}
+	}
+
+	if _, err := EncodingSRI.Encode("md5", sum[:16]); err == nil {
+		t.Error("Expected error for SRI with md5, got nil")
+	}
+	if _, err := ParseEncoding("base85"); err == nil {
+		t.Error("Expected error for unknown encoding, got nil")
+	}
+}
+
+func TestBase58LeadingZeros(t *testing.T) {
+	digest, _ := EncodingBase58.Encode("", []byte{0, 0, 1})
+	if digest != "112" {
+		t.Errorf("Expected 112, got %s", digest)
+	}
+}
+
+func TestHashesEncode(t *testing.T) {
+	hashes, err := HasherMulti(context.Background(), []byte("test data"), "sha256,md5")
+	if err != nil {
+		t.Fatalf("HasherMulti failed: %v", err)
+	}
+
+	if err := hashes.Encode(EncodingHexUpper); err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	if hashes.SHA2.SHA256 != "916F0027A575074CE72A331777C3478D6513F786A591BD892DA1A577BF2335F9" {
+		t.Errorf("Unexpected upper hex digest %s", hashes.SHA2.SHA256)
+	}
+
+	if err := hashes.Encode(EncodingSRI); err == nil {
+		t.Error("Expected error for SRI with md5, got nil")
+	}
+
+	gh, err := ComputeHash(context.Background(), []byte("test data"), "sha256", false)
+	if err != nil {
+		t.Fatalf("ComputeHash failed: %v", err)
+	}
+	if err := gh.Encode("sha256", EncodingSRI); err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	if gh.Digest != "sha256-kW8AJ6V1B0znKjMXd8NHjWUT94alkb2JLaGld78jNfk=" || gh.Encoding != "sri" {
+		t.Errorf("Unexpected SRI digest %s (%s)", gh.Digest, gh.Encoding)
+	}
+}
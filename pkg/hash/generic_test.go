@@ -1,6 +1,8 @@
 package hash_test
 
 import (
+	"context"
+	"encoding/hex"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -20,10 +22,22 @@ var expectedHashesMap = map[string]string{
 	"sha512":           "0e1e21ecf105ec853d24d728867ad70613c21663a4693074b2a3619c1bd39d66b588c33723bb466c72424e80e3ca63c249078ab347bab9428500e7ee43059d0d",
 	"sha512_224":       "9f090221a70db14eb06f6b7d6356dc79aac843ea621dde19dbce4470",
 	"sha512_256":       "9fe875600168548c1954aed4f03974ce06b3e17f03a70980190da2d7ef937a43",
+	"sha3_224":         "c6eaff69274b85cd3450ce7b58c6fc0e3bf7909c57b077e7f27393f0",
 	"sha3_256":         "fc88e0ac33ff105e376f4ece95fb06925d5ab20080dbe3aede7dd47e45dfd931",
+	"sha3_384":         "7c070f6a605d6c5cc34a5aad4ea8aa822a0c925e8ee6d1d4dad0306716650ddf5db87d8926fe495471a4e016983705a9",
 	"sha3_512":         "bb9e2a02237e6f8adcaef9fc14b898b7c80cedc114110472cdf925233621b705963c76e7b113bed3c278ff11671a6d1cdcba545e009ff4c0c02539899241993b",
 	"Shake128":         "ae3bdcf04986a8e7ddd99ac948254693fc32ca6ce3ed278c0c54127f072ba21e",
 	"Shake256":         "be15253026b9a85e01ae54b1939284e8e514fbdad2a3bd5c1c0f437e60548e262dd68c2a2f932847f9610eeb51f8ba1a180ca878c788e900d899538d45c9c4a6",
+	"keccak256":        "7d92c840d5f0ac4f83543201db6005d78414059c778169efa3760f67a451e7ef",
+	"keccak512":        "8ec47653f62877c90050f315b0526b778d90e81cef33d12c18fea17a97bf614f9d06789819a7583a4d3e9d831d331a6340b443158156c0bf52b8d85a6b2462dc",
+	"ripemd160":        "feaf1fb8e0a8cd67d52ac4b437cd0660addd947b",
+	"hash160":          "d66679d3f0a556c6fe5045d2a18798e41a3e2321",
+	"whirlpool":        "bbacd07b4a3201a7baa4f06687cfec93d78a05301131729cb60b06ce4ccaff7a1fcc145477b7db576c3b60eff647241bee08032b6de11236b5db285ab7a429fe",
+	"tiger":            "3a7e85a88788322d419139aba8e91d11f6475ed972a57619",
+	"tiger2":           "43f5c030df86316896e02eb9726627df3c98a90d74cd61c9",
+	"sm3":              "de8fbc52487474ce0f948519a76132ff9cb68e71d2b0a0a765f787c24826fbce",
+	"streebog256":      "a7a23a156990adf879a7009bc518df2f31956ecf8d29edd8d837387cdfcd2cfd",
+	"streebog512":      "ac6ea199422d1cdd07602be57daf13973e147821d690226ea16ff74ad6dd99b7804d922299107a79fa89bf97f01d9d406cdb87d37507710a41ba1f0d7d2a224c",
 	"fnv32":            "c164e31b",
 	"fnv32a":           "578fbe87",
 	"fnv64":            "16d3e0f56019af7b",
@@ -37,6 +51,18 @@ var expectedHashesMap = map[string]string{
 	"blake2b384":       "ecae9bd3d6f47401518d7eb565b7c23d0f64521db101de6d3c3b0e459cd40efd7735717e558d0d8e4ddf8056c8047d6f",
 	"blake2b512":       "21bae505e9cd790bd374e387886738653270888d2b6e0753a1d6ff29b56a30491a7531ae2ec30a75b7446f5e16acb504f8cad64b51e6b6c6f8894368748a3f6b",
 	"blake2s256":       "14414cc07b916d3b83d03e2419cede3b533e450a0207b40454f60079aaac5e2d",
+	"blake3":           "6a953581d60dbebc9749b56d2383277fb02b58d260b4ccf6f119108fa0f1d4ef",
+	"xxh32":            "fa135c8b",
+	"xxh64":            "fa56f7ebf111f1ba",
+	"xxh3":             "8f0fa94a1fe96cc4",
+	"xxh128":           "f012c3aaa2168e2f884ceb29fc98cdfd",
+	"cksum":            "10693ade",
+	"sum_bsd":          "6ac8",
+	"sum_sysv":         "037a",
+	"fletcher16":       "c67d",
+	"fletcher32":       "e298b2ca",
+	"fletcher64":       "461cf8ffe8d4c9f5",
+	"inet_checksum":    "354d",
 }
 
 func TestHash(t *testing.T) {
@@ -44,7 +70,7 @@ func TestHash(t *testing.T) {
 
 	for hashType, expected := range expectedHashesMap {
 		t.Run(hashType, func(t *testing.T) {
-			genericHash, err := ComputeHash(data, hashType, false)
+			genericHash, err := ComputeHash(context.Background(), data, hashType, false)
 			if err != nil {
 				t.Fatalf("ComputeHash failed for %s: %v", hashType, err)
 			}
@@ -68,7 +94,7 @@ func TestHashFile(t *testing.T) {
 
 	for hashType, expected := range expectedHashesMap {
 		t.Run(hashType, func(t *testing.T) {
-			genericHash, err := ComputeHash([]byte(filePath), hashType, true)
+			genericHash, err := ComputeHash(context.Background(), []byte(filePath), hashType, true)
 			if err != nil {
 				t.Fatalf("ComputeHash failed for %s: %v", hashType, err)
 			}
@@ -83,10 +109,13 @@ func TestHashFile(t *testing.T) {
 func TestComputeHashAvailable(t *testing.T) {
 	expectedHashes := []string{
 		"adler32", "md4", "md5", "sha1", "sha224", "sha256", "sha384",
-		"sha512", "sha512_224", "sha512_256", "sha3_256", "sha3_512",
-		"shake128", "shake256", "fnv32", "fnv32a", "fnv64", "fnv64a",
+		"sha512", "sha512_224", "sha512_256", "sha3_224", "sha3_256", "sha3_384",
+		"sha3_512", "shake128", "shake256", "keccak256", "keccak512",
+		"ripemd160", "hash160", "whirlpool", "tiger", "tiger2", "sm3", "streebog256", "streebog512", "fnv32", "fnv32a", "fnv64", "fnv64a",
 		"crc32_ieee", "crc32_koopman", "crc32_castagnoli", "crc64_iso", "crc64_ecma",
-		"blake2b256", "blake2b384", "blake2b512", "blake2s256",
+		"blake2b256", "blake2b384", "blake2b512", "blake2s256", "blake3",
+		"xxh32", "xxh64", "xxh3", "xxh128", "cksum", "sum_bsd", "sum_sysv",
+		"fletcher16", "fletcher32", "fletcher64", "inet_checksum",
 	}
 
 	availableHashes := ComputeHashList()
@@ -105,3 +134,39 @@ func TestComputeHashAvailable(t *testing.T) {
 		t.Errorf("Expected and available hash types do not match.\nExpected: %v\nAvailable: %v", expectedHashes, availableHashes)
 	}
 }
+
+func TestHash160(t *testing.T) {
+	// The compressed secp256k1 generator point and its HASH160, from the
+	// BIP 173 example address bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4.
+	pubKey, _ := hex.DecodeString("0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798")
+
+	gh, err := ComputeHash(context.Background(), pubKey, "hash160", false)
+	if err != nil {
+		t.Fatalf("ComputeHash failed: %v", err)
+	}
+	if expected := "751e76e8199196d454941c45d1b3a323f1433bd6"; gh.HexDigest != expected {
+		t.Errorf("Expected hash160 %s, got %s", expected, gh.HexDigest)
+	}
+}
+
+func TestKeccakLegacyPadding(t *testing.T) {
+	// Well known Ethereum values: the hash of empty input and the selector
+	// of the ERC-20 transfer function.
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"", "c5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470"},
+		{"transfer(address,uint256)", "a9059cbb2ab09eb219583f4a59a5d0623ade346d962bcd4e46b11da047c9049b"},
+	}
+
+	for _, test := range tests {
+		gh, err := ComputeHash(context.Background(), []byte(test.input), "keccak-256", false)
+		if err != nil {
+			t.Fatalf("ComputeHash failed: %v", err)
+		}
+		if gh.HexDigest != test.expected {
+			t.Errorf("keccak256(%q): expected %s, got %s", test.input, test.expected, gh.HexDigest)
+		}
+	}
+}
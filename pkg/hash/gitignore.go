@@ -0,0 +1,139 @@
+package hash
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"strings"
+)
+
+// ignoreRule is a single pattern of a .gitignore file.
+type ignoreRule struct {
+	// base is the slash-separated directory of the .gitignore file,
+	// relative to the walk root. It is empty for the root itself.
+	base    string
+	pattern string
+	negate  bool
+	dirOnly bool
+}
+
+// ignoreRules is the stack of .gitignore rules in effect for a directory,
+// ordered from the walk root down. Later rules take precedence.
+type ignoreRules []ignoreRule
+
+// loadGitignore returns rules extended with the patterns of the .gitignore
+// file in dir, whose path relative to the walk root is base.
+func (rules ignoreRules) loadGitignore(dir, base string) (ignoreRules, error) {
+	file, err := os.Open(dir + string(os.PathSeparator) + ".gitignore")
+	if os.IsNotExist(err) {
+		return rules, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	loaded := append(ignoreRules(nil), rules...)
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if rule, ok := parseIgnoreRule(scanner.Text(), base); ok {
+			loaded = append(loaded, rule)
+		}
+	}
+
+	return loaded, scanner.Err()
+}
+
+func parseIgnoreRule(line, base string) (ignoreRule, bool) {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return ignoreRule{}, false
+	}
+
+	rule := ignoreRule{base: base}
+	if strings.HasPrefix(line, "!") {
+		rule.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, "\\") {
+		line = line[1:]
+	}
+
+	if strings.HasSuffix(line, "/") {
+		rule.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+
+	// Patterns without an inner slash match at any depth; all others are
+	// anchored to the directory of the .gitignore file.
+	if strings.Contains(line, "/") {
+		line = strings.TrimPrefix(line, "/")
+	} else {
+		line = "**/" + line
+	}
+
+	if line == "" {
+		return ignoreRule{}, false
+	}
+
+	rule.pattern = line
+	return rule, true
+}
+
+// ignored reports whether the slash-separated path rel, relative to the walk
+// root, is excluded by the rules.
+func (rules ignoreRules) ignored(rel string, isDir bool) bool {
+	ignored := false
+	for _, rule := range rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+
+		sub := rel
+		if rule.base != "" {
+			if !strings.HasPrefix(rel, rule.base+"/") {
+				continue
+			}
+			sub = rel[len(rule.base)+1:]
+		}
+
+		if matchGlob(rule.pattern, sub) {
+			ignored = !rule.negate
+		}
+	}
+
+	return ignored
+}
+
+// matchGlob reports whether the slash-separated name matches pattern. The
+// pattern uses path.Match syntax per segment, and a "**" segment matches
+// any number of segments, including none.
+func matchGlob(pattern, name string) bool {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(name) == 0 {
+			return false
+		}
+
+		if ok, err := path.Match(pattern[0], name[0]); err != nil || !ok {
+			return false
+		}
+
+		pattern = pattern[1:]
+		name = name[1:]
+	}
+
+	return len(name) == 0
+}
@@ -0,0 +1,27 @@
+package hash
+
+import (
+	"crypto/sha256"
+	"hash"
+
+	"golang.org/x/crypto/ripemd160"
+)
+
+// hash160 is RIPEMD-160 of SHA-256, as used for Bitcoin addresses.
+type hash160 struct {
+	hash.Hash
+}
+
+// NewHash160 returns HASH160, the RIPEMD-160 digest of the SHA-256 digest of
+// the input.
+func NewHash160() hash.Hash {
+	return hash160{sha256.New()}
+}
+
+func (h hash160) Sum(b []byte) []byte {
+	r := ripemd160.New()
+	r.Write(h.Hash.Sum(nil))
+	return r.Sum(b)
+}
+
+func (h hash160) Size() int { return ripemd160.Size }
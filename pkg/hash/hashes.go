@@ -1,39 +1,43 @@
 package hash
 
 import (
-	"crypto/md5"
-	"crypto/sha1"
-	"crypto/sha256"
-	"crypto/sha512"
+	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
 	"hash"
-	"hash/adler32"
-	"hash/crc32"
-	"hash/crc64"
-	"hash/fnv"
-	"io"
-	"os"
-	"sync"
 	"time"
-
-	"golang.org/x/crypto/blake2b"
-	"golang.org/x/crypto/blake2s"
-	"golang.org/x/crypto/md4"
-	"golang.org/x/crypto/sha3"
 )
 
 type Hashes struct {
-	Adler32     string `json:"adler32"`
-	MD4         string `json:"md4"`
-	MD5         string `json:"md5"`
-	SHA1        string `json:"sha1"`
-	SHA2        SHA2   `json:"sha2"`
-	SHA3        SHA3   `json:"sha3"`
-	FNV         FNV    `json:"fnv"`
-	CRC         CRC    `json:"crc"`
-	Blake       Blake  `json:"blake"`
-	Duration    int64  `json:"duration"`
-	DurationStr string `json:"durationStr"`
+	Adler32     string   `json:"adler32"`
+	MD4         string   `json:"md4"`
+	MD5         string   `json:"md5"`
+	SHA1        string   `json:"sha1"`
+	SHA2        SHA2     `json:"sha2"`
+	SHA3        SHA3     `json:"sha3"`
+	RIPEMD      RIPEMD   `json:"ripemd"`
+	Whirlpool   string   `json:"whirlpool"`
+	Tiger       Tiger    `json:"tiger"`
+	SM3         string   `json:"sm3"`
+	Streebog    Streebog `json:"streebog"`
+	FNV         FNV      `json:"fnv"`
+	CRC         CRC      `json:"crc"`
+	Blake       Blake    `json:"blake"`
+	XXHash      XXHash   `json:"xxhash"`
+	Checksum    Checksum `json:"checksum"`
+	Duration    int64    `json:"duration"`
+	DurationStr string   `json:"durationStr"`
+
+	// Extra holds digests of algorithms added with Register, keyed by name.
+	Extra map[string]string `json:"extra,omitempty"`
+
+	// algos lists the algorithms that were computed, in output order.
+	algos []*Algorithm
+	// subset is set when algos is not every registered algorithm.
+	subset bool
+	// sums holds the raw digest of each of algos for Encode.
+	sums [][]byte
 }
 
 type SHA2 struct {
@@ -46,10 +50,33 @@ type SHA2 struct {
 }
 
 type SHA3 struct {
+	SHA224   string `json:"sha3_224"`
 	SHA256   string `json:"sha3_256"`
+	SHA384   string `json:"sha3_384"`
 	SHA512   string `json:"sha3_512"`
 	Shake128 string `json:"shake128"`
 	Shake256 string `json:"shake256"`
+	// Keccak256 and Keccak512 use the original Keccak padding, as used by
+	// Ethereum, rather than the FIPS 202 SHA-3 padding.
+	Keccak256 string `json:"keccak256"`
+	Keccak512 string `json:"keccak512"`
+}
+
+type RIPEMD struct {
+	RIPEMD160 string `json:"ripemd160"`
+	// Hash160 is RIPEMD-160 of SHA-256, as used by Bitcoin.
+	Hash160 string `json:"hash160"`
+}
+
+type Tiger struct {
+	Tiger  string `json:"tiger"`
+	Tiger2 string `json:"tiger2"`
+}
+
+// Streebog holds the GOST R 34.11-2012 digests.
+type Streebog struct {
+	Streebog256 string `json:"streebog256"`
+	Streebog512 string `json:"streebog512"`
 }
 
 type FNV struct {
@@ -72,6 +99,25 @@ type Blake struct {
 	Blake2b384 string `json:"blake2b384"`
 	Blake2b512 string `json:"blake2b512"`
 	Blake2s256 string `json:"blake2s256"`
+	Blake3     string `json:"blake3"`
+}
+
+type XXHash struct {
+	XXH32  string `json:"xxh32"`
+	XXH64  string `json:"xxh64"`
+	XXH3   string `json:"xxh3"`
+	XXH128 string `json:"xxh128"`
+}
+
+// Checksum holds the classic Unix and network checksums.
+type Checksum struct {
+	Cksum      string `json:"cksum"`
+	BSDSum     string `json:"sum_bsd"`
+	SysVSum    string `json:"sum_sysv"`
+	Fletcher16 string `json:"fletcher16"`
+	Fletcher32 string `json:"fletcher32"`
+	Fletcher64 string `json:"fletcher64"`
+	Internet   string `json:"inet_checksum"`
 }
 
 type HasherArray struct {
@@ -79,173 +125,117 @@ type HasherArray struct {
 	Hash string `json:"hash"`
 }
 
-func (h Hashes) Array() []HasherArray {
-	return []HasherArray{
-		{Type: "adler32", Hash: h.Adler32},
-		{Type: "md4", Hash: h.MD4},
-		{Type: "md5", Hash: h.MD5},
-		{Type: "sha1", Hash: h.SHA1},
-		{Type: "sha224", Hash: h.SHA2.SHA224},
-		{Type: "sha256", Hash: h.SHA2.SHA256},
-		{Type: "sha384", Hash: h.SHA2.SHA384},
-		{Type: "sha512", Hash: h.SHA2.SHA512},
-		{Type: "sha512_224", Hash: h.SHA2.SHA512_224},
-		{Type: "sha512_256", Hash: h.SHA2.SHA512_256},
-		{Type: "sha3_256", Hash: h.SHA3.SHA256},
-		{Type: "sha3_512", Hash: h.SHA3.SHA512},
-		{Type: "shake128", Hash: h.SHA3.Shake128},
-		{Type: "shake256", Hash: h.SHA3.Shake256},
-		{Type: "fnv32", Hash: h.FNV.FNV32},
-		{Type: "fnv32a", Hash: h.FNV.FNV32a},
-		{Type: "fnv64", Hash: h.FNV.FNV64},
-		{Type: "fnv64a", Hash: h.FNV.FNV64a},
-		{Type: "crc32_IEEE", Hash: h.CRC.CRC32IEEE},
-		{Type: "crc32_Koopman", Hash: h.CRC.CRC32Koopman},
-		{Type: "crc32_Castagnoli", Hash: h.CRC.CRC32Castagnoli},
-		{Type: "crc64_ISO", Hash: h.CRC.CRC64IOS},
-		{Type: "crc64_ECMA", Hash: h.CRC.CRC64ECMA},
-		{Type: "blake2b256", Hash: h.Blake.Blake2b256},
-		{Type: "blake2b384", Hash: h.Blake.Blake2b384},
-		{Type: "blake2b512", Hash: h.Blake.Blake2b512},
-		{Type: "blake2s256", Hash: h.Blake.Blake2s256},
+// MarshalJSON encodes the digests grouped by family when every registered
+// algorithm was computed. Otherwise the result is a flat object with one
+// member per computed algorithm, in the requested order, followed by the
+// durations.
+func (h Hashes) MarshalJSON() ([]byte, error) {
+	if !h.subset {
+		type grouped Hashes
+		return json.Marshal(grouped(h))
 	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for _, digest := range h.Array() {
+		name, _ := json.Marshal(digest.Type)
+		value, _ := json.Marshal(digest.Hash)
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+		buf.WriteByte(',')
+	}
+	durationStr, _ := json.Marshal(h.DurationStr)
+	fmt.Fprintf(&buf, `"duration":%d,"durationStr":%s}`, h.Duration, durationStr)
+
+	return buf.Bytes(), nil
 }
 
-func initializeHashers() ([]hash.Hash, *Hashes) {
-	hashes := &Hashes{}
-
-	hashers := []hash.Hash{
-		adler32.New(),
-		md4.New(),
-		md5.New(),
-		sha1.New(),
-		sha256.New224(),
-		sha256.New(),
-		sha512.New384(),
-		sha512.New(),
-		sha512.New512_224(),
-		sha512.New512_256(),
-		sha3.New256(),
-		sha3.New512(),
-		sha3.NewShake128(),
-		sha3.NewShake256(),
-		fnv.New32(),
-		fnv.New32a(),
-		fnv.New64(),
-		fnv.New64a(),
-		crc32.NewIEEE(),
-		crc32.New(crc32.MakeTable(crc32.Koopman)),
-		crc32.New(crc32.MakeTable(crc32.Castagnoli)),
-		crc64.New(crc64.MakeTable(crc64.ISO)),
-		crc64.New(crc64.MakeTable(crc64.ECMA)),
+// Array returns the computed digests in order.
+func (h Hashes) Array() []HasherArray {
+	algos := h.algos
+	if algos == nil {
+		algos = algorithms()
 	}
 
-	blake2b256, _ := blake2b.New256(nil)
-	blake2b384, _ := blake2b.New384(nil)
-	blake2b512, _ := blake2b.New512(nil)
-	blake2s256, _ := blake2s.New256(nil)
+	arr := make([]HasherArray, 0, len(algos))
+	for _, a := range algos {
+		if a.field != nil {
+			arr = append(arr, HasherArray{Type: a.displayName(), Hash: *a.field(&h)})
+			continue
+		}
 
-	hashers = append(hashers, blake2b256, blake2b384, blake2b512, blake2s256)
+		if digest, ok := h.Extra[a.Name]; ok {
+			arr = append(arr, HasherArray{Type: a.Name, Hash: digest})
+		}
+	}
 
-	return hashers, hashes
+	return arr
 }
 
-func setHashes(hashes *Hashes, hashers []hash.Hash) {
-	hashes.Adler32 = fmt.Sprintf("%x", hashers[0].Sum(nil))
-	hashes.MD4 = fmt.Sprintf("%x", hashers[1].Sum(nil))
-	hashes.MD5 = fmt.Sprintf("%x", hashers[2].Sum(nil))
-	hashes.SHA1 = fmt.Sprintf("%x", hashers[3].Sum(nil))
-	hashes.SHA2.SHA224 = fmt.Sprintf("%x", hashers[4].Sum(nil))
-	hashes.SHA2.SHA256 = fmt.Sprintf("%x", hashers[5].Sum(nil))
-	hashes.SHA2.SHA384 = fmt.Sprintf("%x", hashers[6].Sum(nil))
-	hashes.SHA2.SHA512 = fmt.Sprintf("%x", hashers[7].Sum(nil))
-	hashes.SHA2.SHA512_224 = fmt.Sprintf("%x", hashers[8].Sum(nil))
-	hashes.SHA2.SHA512_256 = fmt.Sprintf("%x", hashers[9].Sum(nil))
-	hashes.SHA3.SHA256 = fmt.Sprintf("%x", hashers[10].Sum(nil))
-	hashes.SHA3.SHA512 = fmt.Sprintf("%x", hashers[11].Sum(nil))
-	hashes.SHA3.Shake128 = fmt.Sprintf("%x", hashers[12].Sum(nil))
-	hashes.SHA3.Shake256 = fmt.Sprintf("%x", hashers[13].Sum(nil))
-	hashes.FNV.FNV32 = fmt.Sprintf("%x", hashers[14].Sum(nil))
-	hashes.FNV.FNV32a = fmt.Sprintf("%x", hashers[15].Sum(nil))
-	hashes.FNV.FNV64 = fmt.Sprintf("%x", hashers[16].Sum(nil))
-	hashes.FNV.FNV64a = fmt.Sprintf("%x", hashers[17].Sum(nil))
-	hashes.CRC.CRC32IEEE = fmt.Sprintf("%x", hashers[18].Sum(nil))
-	hashes.CRC.CRC32Koopman = fmt.Sprintf("%x", hashers[19].Sum(nil))
-	hashes.CRC.CRC32Castagnoli = fmt.Sprintf("%x", hashers[20].Sum(nil))
-	hashes.CRC.CRC64IOS = fmt.Sprintf("%x", hashers[21].Sum(nil))
-	hashes.CRC.CRC64ECMA = fmt.Sprintf("%x", hashers[22].Sum(nil))
-	hashes.Blake.Blake2b256 = fmt.Sprintf("%x", hashers[23].Sum(nil))
-	hashes.Blake.Blake2b384 = fmt.Sprintf("%x", hashers[24].Sum(nil))
-	hashes.Blake.Blake2b512 = fmt.Sprintf("%x", hashers[25].Sum(nil))
-	hashes.Blake.Blake2s256 = fmt.Sprintf("%x", hashers[26].Sum(nil))
+func initializeHashers(algos []*Algorithm) ([]hash.Hash, *Hashes) {
+	hashers := make([]hash.Hash, len(algos))
+	for i, a := range algos {
+		hashers[i] = a.New()
+	}
+
+	all := algorithms()
+	subset := len(algos) != len(all)
+	for i := 0; !subset && i < len(all); i++ {
+		subset = algos[i] != all[i]
+	}
+
+	return hashers, &Hashes{algos: algos, subset: subset}
 }
 
-func HasherMulti(b []byte) (Hashes, error) {
-	timeStart := time.Now()
-	hashers, hashes := initializeHashers()
+func setHashes(hashes *Hashes, hashers []hash.Hash) {
+	hashes.sums = make([][]byte, len(hashers))
+	for i, h := range hashers {
+		hashes.sums[i] = h.Sum(nil)
+	}
 
-	var wg sync.WaitGroup
-	chunkSize := BufferSize
+	// Hex encoding cannot fail.
+	_ = hashes.Encode(EncodingHex)
+}
 
-	for i := 0; i < len(b); i += chunkSize {
-		end := i + chunkSize
-		if end > len(b) {
-			end = len(b)
+// Encode renders every digest with enc, replacing the hex digests set when
+// the hashes were computed.
+func (h *Hashes) Encode(enc Encoder) error {
+	for i, a := range h.algos {
+		digest, err := enc.Encode(a.Name, h.sums[i])
+		if err != nil {
+			return err
 		}
 
-		chunk := b[i:end]
-		for _, hasher := range hashers {
-			wg.Add(1)
-			go func(h hash.Hash, d []byte) {
-				defer wg.Done()
-				h.Write(d)
-			}(hasher, chunk)
+		if a.field != nil {
+			*a.field(h) = digest
+			continue
 		}
-	}
 
-	wg.Wait()
+		if h.Extra == nil {
+			h.Extra = make(map[string]string)
+		}
+		h.Extra[a.Name] = digest
+	}
 
-	setHashes(hashes, hashers)
-	timeSince := time.Since(timeStart)
-	hashes.Duration = timeSince.Milliseconds()
-	hashes.DurationStr = timeSince.String()
-	return *hashes, nil
+	return nil
 }
 
-func HasherMultiFile(path string) (Hashes, error) {
+// HasherMulti hashes b with the selected algorithms, or every registered
+// algorithm when none are given. Selectors are resolved as by
+// SelectAlgorithms. Each algorithm runs in its own goroutine. Hashing stops
+// with a CanceledError once ctx is done.
+func HasherMulti(ctx context.Context, b []byte, algos ...string) (Hashes, error) {
 	timeStart := time.Now()
-	hashers, hashes := initializeHashers()
 
-	file, err := os.Open(path)
+	selected, err := resolveAlgorithms(algos)
 	if err != nil {
 		return Hashes{}, err
 	}
-	defer file.Close()
-
-	var wg sync.WaitGroup
-	buf := make([]byte, BufferSize)
-
-	for {
-		n, err := file.Read(buf)
-		if err != nil && err != io.EOF {
-			return Hashes{}, err
-		}
-		if n == 0 {
-			break
-		}
-
-		chunk := make([]byte, n)
-		copy(chunk, buf[:n])
+	hashers, hashes := initializeHashers(selected)
 
-		for _, hasher := range hashers {
-			wg.Add(1)
-			go func(h hash.Hash, d []byte) {
-				defer wg.Done()
-				h.Write(d)
-			}(hasher, chunk)
-		}
-
-		wg.Wait()
+	if err := writeBytes(ctx, b, hashers); err != nil {
+		return Hashes{}, err
 	}
 
 	setHashes(hashes, hashers)
@@ -254,3 +244,12 @@ func HasherMultiFile(path string) (Hashes, error) {
 	hashes.DurationStr = timeSince.String()
 	return *hashes, nil
 }
+
+// HasherMultiFile hashes the file at path with the selected algorithms, or
+// every registered algorithm when none are given. The file is read once;
+// each algorithm runs in its own goroutine. Hashing stops with a
+// CanceledError once ctx is done, and progress is reported to an observer
+// attached with WithProgress.
+func HasherMultiFile(ctx context.Context, path string, algos ...string) (Hashes, error) {
+	return HasherMultiFileRange(ctx, path, Range{}, algos...)
+}
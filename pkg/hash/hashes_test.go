@@ -1,8 +1,12 @@
 package hash_test
 
 import (
+	"context"
+	"fmt"
+	"math/rand"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	. "github.com/TechMDW/hashit/pkg/hash"
@@ -22,10 +26,14 @@ var expectedHashes = Hashes{
 		SHA512_256: "9fe875600168548c1954aed4f03974ce06b3e17f03a70980190da2d7ef937a43",
 	},
 	SHA3: SHA3{
-		SHA256:   "fc88e0ac33ff105e376f4ece95fb06925d5ab20080dbe3aede7dd47e45dfd931",
-		SHA512:   "bb9e2a02237e6f8adcaef9fc14b898b7c80cedc114110472cdf925233621b705963c76e7b113bed3c278ff11671a6d1cdcba545e009ff4c0c02539899241993b",
-		Shake128: "ae3bdcf04986a8e7ddd99ac948254693fc32ca6ce3ed278c0c54127f072ba21e",
-		Shake256: "be15253026b9a85e01ae54b1939284e8e514fbdad2a3bd5c1c0f437e60548e262dd68c2a2f932847f9610eeb51f8ba1a180ca878c788e900d899538d45c9c4a6",
+		SHA224:    "c6eaff69274b85cd3450ce7b58c6fc0e3bf7909c57b077e7f27393f0",
+		SHA256:    "fc88e0ac33ff105e376f4ece95fb06925d5ab20080dbe3aede7dd47e45dfd931",
+		SHA384:    "7c070f6a605d6c5cc34a5aad4ea8aa822a0c925e8ee6d1d4dad0306716650ddf5db87d8926fe495471a4e016983705a9",
+		SHA512:    "bb9e2a02237e6f8adcaef9fc14b898b7c80cedc114110472cdf925233621b705963c76e7b113bed3c278ff11671a6d1cdcba545e009ff4c0c02539899241993b",
+		Shake128:  "ae3bdcf04986a8e7ddd99ac948254693fc32ca6ce3ed278c0c54127f072ba21e",
+		Shake256:  "be15253026b9a85e01ae54b1939284e8e514fbdad2a3bd5c1c0f437e60548e262dd68c2a2f932847f9610eeb51f8ba1a180ca878c788e900d899538d45c9c4a6",
+		Keccak256: "7d92c840d5f0ac4f83543201db6005d78414059c778169efa3760f67a451e7ef",
+		Keccak512: "8ec47653f62877c90050f315b0526b778d90e81cef33d12c18fea17a97bf614f9d06789819a7583a4d3e9d831d331a6340b443158156c0bf52b8d85a6b2462dc",
 	},
 	FNV: FNV{
 		FNV32:  "c164e31b",
@@ -45,7 +53,7 @@ var expectedHashes = Hashes{
 func TestHasherMulti(t *testing.T) {
 	data := []byte("test data")
 
-	hashes, err := HasherMulti(data)
+	hashes, err := HasherMulti(context.Background(), data)
 	if err != nil {
 		t.Fatalf("HasherMulti failed: %v", err)
 	}
@@ -63,7 +71,7 @@ func TestHasherMultiFile(t *testing.T) {
 		t.Fatalf("Failed to write test file: %v", err)
 	}
 
-	hashes, err := HasherMultiFile(filePath)
+	hashes, err := HasherMultiFile(context.Background(), filePath)
 	if err != nil {
 		t.Fatalf("HasherMultiFile failed: %v", err)
 	}
@@ -102,9 +110,15 @@ func compareHashes(t *testing.T, actual, expected Hashes) {
 	if actual.SHA2.SHA512_256 != expected.SHA2.SHA512_256 {
 		t.Errorf("Expected SHA512_256 hash %s, got %s", expected.SHA2.SHA512_256, actual.SHA2.SHA512_256)
 	}
+	if actual.SHA3.SHA224 != expected.SHA3.SHA224 {
+		t.Errorf("Expected SHA3-224 hash %s, got %s", expected.SHA3.SHA224, actual.SHA3.SHA224)
+	}
 	if actual.SHA3.SHA256 != expected.SHA3.SHA256 {
 		t.Errorf("Expected SHA3-256 hash %s, got %s", expected.SHA3.SHA256, actual.SHA3.SHA256)
 	}
+	if actual.SHA3.SHA384 != expected.SHA3.SHA384 {
+		t.Errorf("Expected SHA3-384 hash %s, got %s", expected.SHA3.SHA384, actual.SHA3.SHA384)
+	}
 	if actual.SHA3.SHA512 != expected.SHA3.SHA512 {
 		t.Errorf("Expected SHA3-512 hash %s, got %s", expected.SHA3.SHA512, actual.SHA3.SHA512)
 	}
@@ -114,6 +128,12 @@ func compareHashes(t *testing.T, actual, expected Hashes) {
 	if actual.SHA3.Shake256 != expected.SHA3.Shake256 {
 		t.Errorf("Expected Shake256 hash %s, got %s", expected.SHA3.Shake256, actual.SHA3.Shake256)
 	}
+	if actual.SHA3.Keccak256 != expected.SHA3.Keccak256 {
+		t.Errorf("Expected Keccak-256 hash %s, got %s", expected.SHA3.Keccak256, actual.SHA3.Keccak256)
+	}
+	if actual.SHA3.Keccak512 != expected.SHA3.Keccak512 {
+		t.Errorf("Expected Keccak-512 hash %s, got %s", expected.SHA3.Keccak512, actual.SHA3.Keccak512)
+	}
 	if actual.FNV.FNV32 != expected.FNV.FNV32 {
 		t.Errorf("Expected FNV32 hash %s, got %s", expected.FNV.FNV32, actual.FNV.FNV32)
 	}
@@ -144,8 +164,119 @@ func compareHashes(t *testing.T, actual, expected Hashes) {
 }
 
 func TestHasherMultiFile_FileNotExist(t *testing.T) {
-	_, err := HasherMultiFile("nonexistentfile")
+	_, err := HasherMultiFile(context.Background(), "nonexistentfile")
 	if err == nil {
 		t.Fatal("Expected error for nonexistent file, got nil")
 	}
 }
+
+// largeData returns deterministic data spanning several pipeline chunks.
+func largeData(size int) []byte {
+	data := make([]byte, size)
+	rand.New(rand.NewSource(1)).Read(data)
+	return data
+}
+
+func TestHasherMultiLarge(t *testing.T) {
+	data := largeData(2*BufferSize + 12345)
+
+	tempDir := t.TempDir()
+	filePath := filepath.Join(tempDir, "testfile")
+	if err := os.WriteFile(filePath, data, 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	hashes, err := HasherMulti(context.Background(), data)
+	if err != nil {
+		t.Fatalf("HasherMulti failed: %v", err)
+	}
+
+	fileHashes, err := HasherMultiFile(context.Background(), filePath)
+	if err != nil {
+		t.Fatalf("HasherMultiFile failed: %v", err)
+	}
+
+	expected := make(map[string]string)
+	for _, algo := range Algorithms() {
+		h := algo.New()
+		h.Write(data)
+		expected[algo.Name] = fmt.Sprintf("%x", h.Sum(nil))
+	}
+
+	// CRCs are labelled with their historic casing, e.g. crc32_IEEE.
+	for _, h := range hashes.Array() {
+		if h.Hash != expected[strings.ToLower(h.Type)] {
+			t.Errorf("HasherMulti: expected %s hash %s, got %s", h.Type, expected[h.Type], h.Hash)
+		}
+	}
+	for _, h := range fileHashes.Array() {
+		if h.Hash != expected[strings.ToLower(h.Type)] {
+			t.Errorf("HasherMultiFile: expected %s hash %s, got %s", h.Type, expected[h.Type], h.Hash)
+		}
+	}
+}
+
+func TestHashesCRCLabels(t *testing.T) {
+	hashes, err := HasherMulti(context.Background(), []byte("test data"), "crc")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"crc32_IEEE", "crc32_Koopman", "crc32_Castagnoli", "crc64_ISO", "crc64_ECMA"}
+	arr := hashes.Array()
+	if len(arr) != len(expected) {
+		t.Fatalf("Expected %d hashes, got %d", len(expected), len(arr))
+	}
+	for i, label := range expected {
+		if arr[i].Type != label {
+			t.Errorf("Expected label %s, got %s", label, arr[i].Type)
+		}
+	}
+}
+
+const benchmarkSize = 64 * 1024 * 1024
+
+func BenchmarkHasherMulti(b *testing.B) {
+	data := largeData(benchmarkSize)
+
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := HasherMulti(context.Background(), data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkHasherMultiFile(b *testing.B) {
+	filePath := filepath.Join(b.TempDir(), "benchfile")
+	if err := os.WriteFile(filePath, largeData(benchmarkSize), 0644); err != nil {
+		b.Fatal(err)
+	}
+
+	b.SetBytes(benchmarkSize)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := HasherMultiFile(context.Background(), filePath); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkAlgorithm measures each algorithm on its own. Given at least as
+// many CPUs as algorithms, the multi-hash benchmarks above should come close
+// to the slowest of these.
+func BenchmarkAlgorithm(b *testing.B) {
+	data := largeData(benchmarkSize)
+
+	for _, algo := range Algorithms() {
+		b.Run(algo.Name, func(b *testing.B) {
+			b.SetBytes(int64(len(data)))
+			for i := 0; i < b.N; i++ {
+				h := algo.New()
+				h.Write(data)
+				h.Sum(nil)
+			}
+		})
+	}
+}
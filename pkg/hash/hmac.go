@@ -0,0 +1,169 @@
+package hash
+
+import (
+	"context"
+	"crypto/hmac"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// hmacKeyed returns a NewKeyed constructor that wraps newHash in HMAC.
+func hmacKeyed(newHash func() hash.Hash) func(key []byte) (hash.Hash, error) {
+	return func(key []byte) (hash.Hash, error) {
+		return hmac.New(newHash, key), nil
+	}
+}
+
+// newKeyedHasher returns a keyed instance of the named algorithm and its
+// canonical name.
+func newKeyedHasher(hashType string, key []byte) (string, hash.Hash, error) {
+	algo, err := lookup(hashType)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if algo.NewKeyed == nil {
+		return "", nil, fmt.Errorf("hash type %s does not support keyed hashing", algo.Name)
+	}
+
+	h, err := algo.NewKeyed(key)
+	return algo.Name, h, err
+}
+
+// ComputeHMAC returns a keyed hash (MAC) of the data using the specified
+// hash type. Merkle–Damgård hashes such as MD5, SHA-1 and SHA-2 use HMAC,
+// BLAKE2 uses its native keyed mode and SHA-3 uses KMAC.
+func ComputeHMAC(ctx context.Context, data []byte, hashType string, key []byte, file bool) (*GenericHash, error) {
+	name, h, err := newKeyedHasher(hashType, key)
+	if err != nil {
+		return &GenericHash{}, err
+	}
+
+	return compute(ctx, data, name, h, file)
+}
+
+// ComputeHMACReader returns a keyed hash of everything read from r using the
+// specified hash type.
+func ComputeHMACReader(ctx context.Context, r io.Reader, hashType string, key []byte) (*GenericHash, error) {
+	name, h, err := newKeyedHasher(hashType, key)
+	if err != nil {
+		return &GenericHash{}, err
+	}
+
+	return hashReader(ctx, r, name, h)
+}
+
+// Verify reports whether the hash equals the hex encoded expected value.
+// The comparison runs in constant time so it is safe for MACs.
+func (gh *GenericHash) Verify(expected string) bool {
+	b, err := hex.DecodeString(expected)
+	if err != nil {
+		return false
+	}
+
+	return hmac.Equal(gh.HashBytes, b)
+}
+
+// kmac implements KMAC128 and KMAC256 from NIST SP 800-185 on top of
+// cSHAKE.
+type kmac struct {
+	shake  sha3.ShakeHash
+	prefix []byte
+	size   int
+	rate   int
+}
+
+// newKMAC returns KMAC128 (security 128) or KMAC256 (security 256) with an
+// output of size bytes and the customization string custom.
+func newKMAC(security int, key []byte, size int, custom string) hash.Hash {
+	var shake sha3.ShakeHash
+	var rate int
+	if security == 128 {
+		shake = sha3.NewCShake128([]byte("KMAC"), []byte(custom))
+		rate = 168
+	} else {
+		shake = sha3.NewCShake256([]byte("KMAC"), []byte(custom))
+		rate = 136
+	}
+
+	k := &kmac{
+		shake:  shake,
+		prefix: bytepad(encodeString(key), rate),
+		size:   size,
+		rate:   rate,
+	}
+	k.shake.Write(k.prefix)
+
+	return k
+}
+
+func kmacKeyed(security, size int) func(key []byte) (hash.Hash, error) {
+	return func(key []byte) (hash.Hash, error) {
+		return newKMAC(security, key, size, ""), nil
+	}
+}
+
+func (k *kmac) Write(p []byte) (int, error) { return k.shake.Write(p) }
+func (k *kmac) Size() int                   { return k.size }
+func (k *kmac) BlockSize() int              { return k.rate }
+
+func (k *kmac) Reset() {
+	k.shake.Reset()
+	k.shake.Write(k.prefix)
+}
+
+func (k *kmac) Sum(b []byte) []byte {
+	d := k.shake.Clone()
+	d.Write(rightEncode(uint64(k.size) * 8))
+
+	out := make([]byte, k.size)
+	d.Read(out)
+
+	return append(b, out...)
+}
+
+// leftEncode, rightEncode, encodeString and bytepad are the encodings
+// defined in NIST SP 800-185 section 2.3.
+func leftEncode(x uint64) []byte {
+	var buf [9]byte
+	binary.BigEndian.PutUint64(buf[1:], x)
+
+	i := 1
+	for i < 8 && buf[i] == 0 {
+		i++
+	}
+	buf[i-1] = byte(9 - i)
+
+	return buf[i-1:]
+}
+
+func rightEncode(x uint64) []byte {
+	var buf [9]byte
+	binary.BigEndian.PutUint64(buf[:8], x)
+
+	i := 0
+	for i < 7 && buf[i] == 0 {
+		i++
+	}
+	buf[8] = byte(8 - i)
+
+	return buf[i:]
+}
+
+func encodeString(s []byte) []byte {
+	return append(leftEncode(uint64(len(s))*8), s...)
+}
+
+func bytepad(x []byte, w int) []byte {
+	out := append(leftEncode(uint64(w)), x...)
+	if pad := len(out) % w; pad != 0 {
+		out = append(out, make([]byte, w-pad)...)
+	}
+
+	return out
+}
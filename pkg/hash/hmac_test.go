@@ -0,0 +1,150 @@
+package hash_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/TechMDW/hashit/pkg/hash"
+)
+
+var hmacKey = []byte("secret key")
+
+// expectedHMACMap holds MACs of "test data" under hmacKey, computed with
+// openssl mac (HMAC, KMAC128, KMAC256, BLAKE2BMAC and BLAKE2SMAC).
+var expectedHMACMap = map[string]string{
+	"md5":        "38b9efb40194ba20bb3f7400bf919c34",
+	"sha1":       "63e6eb9d62ee0655d1ded49859ce33ea23ab76cb",
+	"sha224":     "e0199ed1b97721541556858f1b5177ad33764a12fe750aea3d98642f",
+	"sha256":     "d51c4289e6eea49db00925bb7a948d31309550040f88bc4aba39bb3107c071be",
+	"sha384":     "96ae72036747b218f5605480fafb25d6975f2fa2fc0d629f2ad8da5ab3dcb8476d2ed09a64655bcc73dcec6ef28d8d3a",
+	"sha512":     "caaea57dc79d8d1588638163c4fc7e68ae56caa0f5e4882218308be1fa23d346c647547f3359ed452c30d92c9581ad64682994c63d13cd76bf4446f72375482b",
+	"sha512_224": "a901af7bfa4074be3509d86d0528ba531123e5651ed85f9c324ab908",
+	"sha512_256": "e493fa9f71c73d5cabe30edf8392e1031fe865c84bd16cdd65e93ef3c872b423",
+	"sha3_256":   "4c8e2fe4afd3cdc465468cd16bb39238e15df22a67ef3674b44e7611862719dd",
+	"sha3_224":   "7e867203dda9c3a3a536f3ac0850144690654f9ff9d5afd6024d9765",
+	"sha3_384":   "3e1d4d607a864980970a34adfdba1ca5a8d6de18d29bd42d9846b4083070512fdd917e86fe522100ca71919b8724d162",
+	"sha3_512":   "749d0f3756cabd8abed0575513d6edcba48d4cb37c08602ee1ed986934c7edede663058dc115e310019f66f2bfb478d0c1a4d0cda5e806073ff4db86a03b57ad",
+	"shake128":   "4c8e2fe4afd3cdc465468cd16bb39238e15df22a67ef3674b44e7611862719dd",
+	"shake256":   "749d0f3756cabd8abed0575513d6edcba48d4cb37c08602ee1ed986934c7edede663058dc115e310019f66f2bfb478d0c1a4d0cda5e806073ff4db86a03b57ad",
+	"blake2b256": "cce472c9c9d03d2511d15daf19390499e09ecc55488490e2363a2d13468d8563",
+	"blake2b384": "d33b8e46ba005ff7bc7d7d4eb433afe84d438ba854359dd32b6c25969b66c33da0e35c4f4e6bbf07ff5e1dd64fd0561c",
+	"blake2b512": "f8845e08d58a91bca8c56903ac01dd147ce96f82acca3aff0b949b84b730702b3ee4628a2e2ff63bc978ba41159f4a93ddf9ccc427d0e6ceb62fdbf40b08a3dd",
+	"blake2s256": "bb20a794b02401110402a94216718999b1ec349cdeed276e4facbc94a496a5c6",
+	"ripemd160":  "d6e51df646e518cc531896b18a90de6e064a1b58",
+	"whirlpool":  "ead024454c47c25c1e458498426c314408e26263742348ab66ef8392a60e006c5de254a87817a9a65cd27cf6ad9a911ad423f5d5c0033e9ca574412f82c540a3",
+	"sm3":        "3df1bb73bbb2f3635c2e8b3cf720fc6a3bb6f3c122b5ec5e461fd59cf0067fb4",
+}
+
+func TestComputeHMAC(t *testing.T) {
+	data := []byte("test data")
+
+	for hashType, expected := range expectedHMACMap {
+		t.Run(hashType, func(t *testing.T) {
+			gh, err := ComputeHMAC(context.Background(), data, hashType, hmacKey, false)
+			if err != nil {
+				t.Fatalf("ComputeHMAC failed for %s: %v", hashType, err)
+			}
+
+			if gh.HexDigest != expected {
+				t.Errorf("Expected %s MAC %s, got %s", hashType, expected, gh.HexDigest)
+			}
+		})
+	}
+}
+
+func TestComputeHMACFileAndReader(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "testfile")
+	if err := os.WriteFile(filePath, []byte("test data"), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	gh, err := ComputeHMAC(context.Background(), []byte(filePath), "sha256", hmacKey, true)
+	if err != nil {
+		t.Fatalf("ComputeHMAC failed: %v", err)
+	}
+	if gh.HexDigest != expectedHMACMap["sha256"] {
+		t.Errorf("Expected MAC %s, got %s", expectedHMACMap["sha256"], gh.HexDigest)
+	}
+
+	gh, err = ComputeHMACReader(context.Background(), strings.NewReader("test data"), "blake2s256", hmacKey)
+	if err != nil {
+		t.Fatalf("ComputeHMACReader failed: %v", err)
+	}
+	if gh.HexDigest != expectedHMACMap["blake2s256"] {
+		t.Errorf("Expected MAC %s, got %s", expectedHMACMap["blake2s256"], gh.HexDigest)
+	}
+}
+
+// TestKMACSample checks sample #1 of the NIST SP 800-185 KMAC examples.
+func TestKMACSample(t *testing.T) {
+	key := make([]byte, 32)
+	for i := range key {
+		key[i] = byte(0x40 + i)
+	}
+
+	gh, err := ComputeHMAC(context.Background(), []byte{0, 1, 2, 3}, "sha3_256", key, false)
+	if err != nil {
+		t.Fatalf("ComputeHMAC failed: %v", err)
+	}
+
+	expected := "e5780b0d3ea6f7d3a429c5706aa43a00fadbd7d49628839e3187243f456ee14e"
+	if gh.HexDigest != expected {
+		t.Errorf("Expected KMAC128 %s, got %s", expected, gh.HexDigest)
+	}
+}
+
+func TestComputeHMACUnsupported(t *testing.T) {
+	if _, err := ComputeHMAC(context.Background(), []byte("test data"), "fnv32", hmacKey, false); err == nil {
+		t.Error("Expected error for fnv32, got nil")
+	}
+
+	if _, err := ComputeHMAC(context.Background(), []byte("test data"), "blake2s256", make([]byte, 33), false); err == nil {
+		t.Error("Expected error for an oversized BLAKE2s key, got nil")
+	}
+}
+
+func TestGenericHashVerify(t *testing.T) {
+	gh, err := ComputeHMAC(context.Background(), []byte("test data"), "sha256", hmacKey, false)
+	if err != nil {
+		t.Fatalf("ComputeHMAC failed: %v", err)
+	}
+
+	if !gh.Verify(expectedHMACMap["sha256"]) {
+		t.Error("Expected Verify to accept the correct MAC")
+	}
+	if !gh.Verify(strings.ToUpper(expectedHMACMap["sha256"])) {
+		t.Error("Expected Verify to accept an uppercase MAC")
+	}
+	if gh.Verify(expectedHMACMap["sha1"]) {
+		t.Error("Expected Verify to reject a different MAC")
+	}
+	if gh.Verify("not hex") {
+		t.Error("Expected Verify to reject invalid hex")
+	}
+}
+
+// Reference values computed with github.com/zeebo/blake3.
+func TestBlake3KeyedAndDeriveKey(t *testing.T) {
+	gh, err := ComputeHMAC(context.Background(), []byte("test data"), "blake3", []byte("0123456789abcdef0123456789abcdef"), false)
+	if err != nil {
+		t.Fatalf("ComputeHMAC failed: %v", err)
+	}
+	if expected := "ed5263915b29a9394957c6bf6cc7718b2474274aa0762aefd9b3d1ffbff35105"; gh.HexDigest != expected {
+		t.Errorf("Expected keyed BLAKE3 %s, got %s", expected, gh.HexDigest)
+	}
+
+	if _, err := ComputeHMAC(context.Background(), []byte("test data"), "blake3", hmacKey, false); err == nil {
+		t.Error("Expected error for a BLAKE3 key that is not 32 bytes, got nil")
+	}
+
+	gh, err = ComputeDeriveKeyReader(context.Background(), strings.NewReader("test data"), "hashit test context")
+	if err != nil {
+		t.Fatalf("ComputeDeriveKeyReader failed: %v", err)
+	}
+	if expected := "3d4d9e6463259bd39888bdc750b601a7e8e287c19c378c1e25ac917bffc396aa"; gh.HexDigest != expected {
+		t.Errorf("Expected derived key %s, got %s", expected, gh.HexDigest)
+	}
+}
@@ -0,0 +1,347 @@
+package hash
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ManifestFormat is an output format for a list of file digests.
+type ManifestFormat int
+
+const (
+	// ManifestCoreutils writes "<hex>  <path>" lines as sha256sum does. It
+	// supports a single algorithm.
+	ManifestCoreutils ManifestFormat = iota
+	// ManifestBSD writes "SHA256 (<path>) = <hex>" lines, one per algorithm.
+	ManifestBSD
+	// ManifestJSONLines writes one JSON object per file.
+	ManifestJSONLines
+	// ManifestCSV writes a header followed by one row per file.
+	ManifestCSV
+	// ManifestNative writes the lines of the traditional tool of each
+	// algorithm, as by NativeLine, e.g. "<crc> <bytes> <path>" for cksum.
+	ManifestNative
+)
+
+// ParseManifestFormat parses "coreutils", "bsd", "jsonl", "csv" or "native".
+func ParseManifestFormat(s string) (ManifestFormat, error) {
+	switch strings.ToLower(s) {
+	case "coreutils", "gnu":
+		return ManifestCoreutils, nil
+	case "bsd", "tag":
+		return ManifestBSD, nil
+	case "jsonl", "json":
+		return ManifestJSONLines, nil
+	case "csv":
+		return ManifestCSV, nil
+	case "native":
+		return ManifestNative, nil
+	default:
+		return 0, fmt.Errorf("unknown manifest format: %s", s)
+	}
+}
+
+// ManifestEntry holds the digests of one file.
+type ManifestEntry struct {
+	Path   string
+	Size   int64
+	Hashes Hashes
+	// Err is set when the file could not be hashed.
+	Err error
+}
+
+// HashFiles hashes files with the named algorithms using up to workers
+// files at a time; workers <= 0 means one per CPU. Entries are returned in
+// the order of files, and per-file failures are reported in
+// ManifestEntry.Err. Once ctx is done it stops with a CanceledError counting
+// the files and bytes hashed so far.
+func HashFiles(ctx context.Context, files []FileEntry, algos []string, workers int) ([]ManifestEntry, error) {
+	if _, err := resolveAlgorithms(algos); err != nil {
+		return nil, err
+	}
+
+	ctx = withoutProgress(ctx)
+	entries := make([]ManifestEntry, len(files))
+	forEachParallel(ctx, len(files), workers, func(i int) {
+		entries[i] = hashFileEntry(ctx, files[i], algos)
+	})
+
+	if ctx.Err() != nil {
+		cerr := &CanceledError{Err: context.Cause(ctx)}
+		for _, entry := range entries {
+			var partial *CanceledError
+			switch {
+			case errors.As(entry.Err, &partial):
+				cerr.Processed += partial.Processed
+			case entry.Err == nil && entry.Path != "":
+				// Files that were never started have no path.
+				cerr.Files++
+				cerr.Processed += entry.Size
+			}
+		}
+		return nil, cerr
+	}
+
+	return entries, nil
+}
+
+// forEachParallel calls fn for 0 <= i < n from up to workers goroutines;
+// workers <= 0 means one per CPU. It stops handing out work once ctx is
+// done.
+func forEachParallel(ctx context.Context, n, workers int, fn func(i int)) {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				fn(j)
+			}
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		if ctx.Err() != nil {
+			break
+		}
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+func hashFileEntry(ctx context.Context, f FileEntry, algos []string) ManifestEntry {
+	entry := ManifestEntry{Path: f.Path}
+
+	if f.Link != "" {
+		entry.Size = int64(len(f.Link))
+		entry.Hashes, entry.Err = hashFileContent(ctx, strings.NewReader(f.Link), algos)
+		return entry
+	}
+
+	file, err := os.Open(f.OSPath)
+	if err != nil {
+		entry.Err = err
+		return entry
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		entry.Err = err
+		return entry
+	}
+
+	entry.Size = info.Size()
+	entry.Hashes, entry.Err = hashFileContent(ctx, file, algos)
+	return entry
+}
+
+// fileBuffers holds the buffers hashFileContent reads files into, so that
+// hashing a tree of small files does not allocate for every file.
+var fileBuffers = sync.Pool{
+	New: func() any {
+		buf := make([]byte, BufferSize)
+		return &buf
+	},
+}
+
+// hashFileContent hashes everything read from r as HashReader does. Input
+// that fits in BufferSize, as most files of a tree do, is written to each
+// hasher at once from a pooled buffer, without the buffers and goroutines
+// of the pipeline.
+func hashFileContent(ctx context.Context, r io.Reader, algos []string) (Hashes, error) {
+	timeStart := time.Now()
+
+	bufp := fileBuffers.Get().(*[]byte)
+	defer fileBuffers.Put(bufp)
+	buf := *bufp
+
+	if err := canceled(ctx, 0); err != nil {
+		return Hashes{}, err
+	}
+	n, err := readChunk(r, buf)
+	if err != nil {
+		return Hashes{}, err
+	}
+	if n == len(buf) {
+		return HashReader(ctx, io.MultiReader(bytes.NewReader(buf), r), algos...)
+	}
+
+	selected, err := resolveAlgorithms(algos)
+	if err != nil {
+		return Hashes{}, err
+	}
+	hashers, hashes := initializeHashers(selected)
+	for _, h := range hashers {
+		h.Write(buf[:n])
+	}
+
+	setHashes(hashes, hashers)
+	timeSince := time.Since(timeStart)
+	hashes.Duration = timeSince.Milliseconds()
+	hashes.DurationStr = timeSince.String()
+	return *hashes, nil
+}
+
+// WriteManifest writes the entries that were hashed successfully in the
+// given format.
+func WriteManifest(w io.Writer, entries []ManifestEntry, format ManifestFormat) error {
+	switch format {
+	case ManifestCoreutils:
+		return writeCoreutilsManifest(w, entries)
+	case ManifestBSD:
+		return writeBSDManifest(w, entries)
+	case ManifestJSONLines:
+		return writeJSONLinesManifest(w, entries)
+	case ManifestCSV:
+		return writeCSVManifest(w, entries)
+	case ManifestNative:
+		return writeNativeManifest(w, entries)
+	default:
+		return fmt.Errorf("unknown manifest format: %d", format)
+	}
+}
+
+func writeCoreutilsManifest(w io.Writer, entries []ManifestEntry) error {
+	for _, entry := range entries {
+		if entry.Err != nil {
+			continue
+		}
+
+		digests := entry.Hashes.Array()
+		if len(digests) != 1 {
+			return fmt.Errorf("coreutils format needs exactly one hash type, got %d", len(digests))
+		}
+
+		prefix, path := escapeChecksumPath(entry.Path)
+		if _, err := fmt.Fprintf(w, "%s%s  %s\n", prefix, digests[0].Hash, path); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writeBSDManifest(w io.Writer, entries []ManifestEntry) error {
+	for _, entry := range entries {
+		if entry.Err != nil {
+			continue
+		}
+
+		prefix, path := escapeChecksumPath(entry.Path)
+		for _, digest := range entry.Hashes.Array() {
+			if _, err := fmt.Fprintf(w, "%s%s (%s) = %s\n", prefix, BSDTag(digest.Type), path, digest.Hash); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func writeJSONLinesManifest(w io.Writer, entries []ManifestEntry) error {
+	enc := json.NewEncoder(w)
+	for _, entry := range entries {
+		if entry.Err != nil {
+			continue
+		}
+
+		line := struct {
+			Path   string            `json:"path"`
+			Size   int64             `json:"size"`
+			Hashes map[string]string `json:"hashes"`
+		}{
+			Path:   entry.Path,
+			Size:   entry.Size,
+			Hashes: make(map[string]string),
+		}
+		for _, digest := range entry.Hashes.Array() {
+			line.Hashes[digest.Type] = digest.Hash
+		}
+
+		if err := enc.Encode(line); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writeCSVManifest(w io.Writer, entries []ManifestEntry) error {
+	cw := csv.NewWriter(w)
+
+	header := false
+	for _, entry := range entries {
+		if entry.Err != nil {
+			continue
+		}
+
+		digests := entry.Hashes.Array()
+		if !header {
+			row := []string{"path", "size"}
+			for _, digest := range digests {
+				row = append(row, digest.Type)
+			}
+			if err := cw.Write(row); err != nil {
+				return err
+			}
+			header = true
+		}
+
+		row := []string{entry.Path, strconv.FormatInt(entry.Size, 10)}
+		for _, digest := range digests {
+			row = append(row, digest.Hash)
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func writeNativeManifest(w io.Writer, entries []ManifestEntry) error {
+	for _, entry := range entries {
+		if entry.Err != nil {
+			continue
+		}
+
+		for i, a := range entry.Hashes.algos {
+			if _, err := fmt.Fprintln(w, NativeLine(a.Name, entry.Hashes.sums[i], entry.Size, entry.Path)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// escapeChecksumPath escapes file names the way coreutils does, returning
+// the "\\" line prefix that marks an escaped name.
+func escapeChecksumPath(path string) (string, string) {
+	if !strings.ContainsAny(path, "\\\n\r") {
+		return "", path
+	}
+
+	r := strings.NewReplacer("\\", "\\\\", "\n", "\\n", "\r", "\\r")
+	return "\\", r.Replace(path)
+}
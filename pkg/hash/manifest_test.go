@@ -0,0 +1,155 @@
+package hash_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/TechMDW/hashit/pkg/hash"
+)
+
+func manifestEntries(t *testing.T, algos ...string) []ManifestEntry {
+	t.Helper()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "test"), []byte("test data"), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "new\nline"), []byte("test data"), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	files := []FileEntry{
+		{Path: "test", OSPath: filepath.Join(dir, "test")},
+		{Path: "new\nline", OSPath: filepath.Join(dir, "new\nline")},
+		{Path: "missing", OSPath: filepath.Join(dir, "missing")},
+	}
+
+	entries, err := HashFiles(context.Background(), files, algos, 2)
+	if err != nil {
+		t.Fatalf("HashFiles failed: %v", err)
+	}
+
+	return entries
+}
+
+func TestHashFiles(t *testing.T) {
+	entries := manifestEntries(t, "md5")
+
+	if entries[0].Err != nil || entries[0].Hashes.MD5 != expectedHashes.MD5 || entries[0].Size != 9 {
+		t.Errorf("Unexpected entry %+v", entries[0])
+	}
+	if entries[2].Err == nil {
+		t.Error("Expected error for missing file, got nil")
+	}
+}
+
+func TestHashFilesSizes(t *testing.T) {
+	dir := t.TempDir()
+	data := largeData(2*BufferSize + 1)
+
+	var files []FileEntry
+	sizes := []int{0, 1, BufferSize - 1, BufferSize, BufferSize + 1, 2*BufferSize + 1}
+	for _, size := range sizes {
+		name := fmt.Sprintf("%d", size)
+		if err := os.WriteFile(filepath.Join(dir, name), data[:size], 0644); err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, FileEntry{Path: name, OSPath: filepath.Join(dir, name)})
+	}
+
+	entries, err := HashFiles(context.Background(), files, []string{"sha256"}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, size := range sizes {
+		want := sha256.Sum256(data[:size])
+		if entries[i].Err != nil || entries[i].Hashes.SHA2.SHA256 != hex.EncodeToString(want[:]) {
+			t.Errorf("File of %d bytes: expected %x, got %+v", size, want, entries[i])
+		}
+	}
+}
+
+func TestWriteManifest(t *testing.T) {
+	tests := []struct {
+		format   ManifestFormat
+		algos    []string
+		expected string
+	}{
+		{
+			format: ManifestCoreutils,
+			algos:  []string{"md5"},
+			expected: "eb733a00c0c9d336e65691a37ab54293  test\n" +
+				"\\eb733a00c0c9d336e65691a37ab54293  new\\nline\n",
+		},
+		{
+			format: ManifestBSD,
+			algos:  []string{"md5", "sha1"},
+			expected: "MD5 (test) = eb733a00c0c9d336e65691a37ab54293\n" +
+				"SHA1 (test) = f48dd853820860816c75d54d0f584dc863327a7c\n" +
+				"\\MD5 (new\\nline) = eb733a00c0c9d336e65691a37ab54293\n" +
+				"\\SHA1 (new\\nline) = f48dd853820860816c75d54d0f584dc863327a7c\n",
+		},
+		{
+			format: ManifestJSONLines,
+			algos:  []string{"md5"},
+			expected: `{"path":"test","size":9,"hashes":{"md5":"eb733a00c0c9d336e65691a37ab54293"}}` + "\n" +
+				`{"path":"new\nline","size":9,"hashes":{"md5":"eb733a00c0c9d336e65691a37ab54293"}}` + "\n",
+		},
+		{
+			format: ManifestCSV,
+			algos:  []string{"sha1", "md5"},
+			expected: "path,size,sha1,md5\n" +
+				"test,9,f48dd853820860816c75d54d0f584dc863327a7c,eb733a00c0c9d336e65691a37ab54293\n" +
+				"\"new\nline\",9,f48dd853820860816c75d54d0f584dc863327a7c,eb733a00c0c9d336e65691a37ab54293\n",
+		},
+		{
+			format: ManifestNative,
+			algos:  []string{"cksum", "sum_bsd"},
+			expected: "275331806 9 test\n" +
+				"27336     1 test\n" +
+				"275331806 9 new\nline\n" +
+				"27336     1 new\nline\n",
+		},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := WriteManifest(&buf, manifestEntries(t, tt.algos...), tt.format); err != nil {
+			t.Fatalf("WriteManifest failed: %v", err)
+		}
+
+		if buf.String() != tt.expected {
+			t.Errorf("Format %d: expected\n%s\ngot\n%s", tt.format, tt.expected, buf.String())
+		}
+	}
+}
+
+func TestWriteManifestCoreutilsSingleHash(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteManifest(&buf, manifestEntries(t, "md5", "sha1"), ManifestCoreutils); err == nil {
+		t.Error("Expected error for several hash types, got nil")
+	}
+}
+
+func TestManifestRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteManifest(&buf, manifestEntries(t, "md5"), ManifestCoreutils); err != nil {
+		t.Fatalf("WriteManifest failed: %v", err)
+	}
+
+	for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+		cl, err := ParseChecksumLine(string(line))
+		if err != nil {
+			t.Fatalf("ParseChecksumLine(%q) failed: %v", line, err)
+		}
+		if cl.Digest != expectedHashes.MD5 {
+			t.Errorf("Expected digest %s, got %s", expectedHashes.MD5, cl.Digest)
+		}
+	}
+}
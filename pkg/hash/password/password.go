@@ -0,0 +1,424 @@
+// Package password produces and verifies password hashes for bcrypt, scrypt,
+// Argon2 and PBKDF2.
+//
+// bcrypt hashes use the modular crypt format ("$2a$12$..."). All other
+// algorithms use the PHC string format with unpadded standard base64 salt
+// and key:
+//
+//	$argon2id$v=19$m=65536,t=3,p=4$<salt>$<key>
+//	$scrypt$ln=17,r=8,p=1$<salt>$<key>
+//	$pbkdf2-sha256$i=600000,l=32$<salt>$<key>
+//
+// Parse also accepts the passlib PBKDF2 layout
+// ("$pbkdf2-sha256$<iterations>$<salt>$<key>" with adapted base64).
+package password
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"hash"
+	"math"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+)
+
+// Algorithm names a password hashing scheme. The value is the identifier
+// used in encoded hashes.
+type Algorithm string
+
+const (
+	Bcrypt       Algorithm = "bcrypt"
+	Scrypt       Algorithm = "scrypt"
+	Argon2i      Algorithm = "argon2i"
+	Argon2id     Algorithm = "argon2id"
+	PBKDF2SHA256 Algorithm = "pbkdf2-sha256"
+	PBKDF2SHA512 Algorithm = "pbkdf2-sha512"
+)
+
+// Algorithms lists the supported algorithms.
+var Algorithms = []Algorithm{Bcrypt, Scrypt, Argon2i, Argon2id, PBKDF2SHA256, PBKDF2SHA512}
+
+// ErrInvalidHash is returned for encoded hashes that cannot be parsed.
+var ErrInvalidHash = errors.New("invalid password hash")
+
+// argon2Version is the only Argon2 version produced by x/crypto/argon2.
+const argon2Version = argon2.Version
+
+// Upper bounds on the cost parameters. Encoded hashes are often untrusted
+// input, and a forged one could otherwise make Verify allocate gigabytes or
+// run practically forever. The bounds lie well above every recommended
+// setting: 2 GiB is the memory of the first RFC 9106 Argon2 recommendation.
+const (
+	// maxBcryptCost allows 64 times the work of the recommended cost of 12.
+	maxBcryptCost   = 18
+	maxArgon2Memory = 2 << 20 // KiB
+	maxArgon2Time   = 100
+	// maxScryptMemory bounds N·r, which takes 128·N·r bytes.
+	maxScryptMemory = 1 << 24
+	// maxScryptWork bounds N·r·p.
+	maxScryptWork    = 1 << 26
+	maxPBKDF2Rounds  = 10_000_000
+	maxSaltKeyLength = 1024
+)
+
+// Params holds the cost parameters of an algorithm. Only the fields used by
+// the algorithm are relevant.
+type Params struct {
+	// Cost is the bcrypt cost (log2 of the number of rounds).
+	Cost int `json:"cost,omitempty"`
+	// LogN is the scrypt CPU/memory cost as log2(N).
+	LogN int `json:"logN,omitempty"`
+	// BlockSize is the scrypt block size r.
+	BlockSize int `json:"blockSize,omitempty"`
+	// Time is the number of Argon2 passes.
+	Time uint32 `json:"time,omitempty"`
+	// Memory is the Argon2 memory in KiB.
+	Memory uint32 `json:"memory,omitempty"`
+	// Parallelism is the Argon2 lane count or the scrypt p.
+	Parallelism int `json:"parallelism,omitempty"`
+	// Iterations is the PBKDF2 iteration count.
+	Iterations int `json:"iterations,omitempty"`
+	// SaltLength is the salt size in bytes. bcrypt always uses 16.
+	SaltLength int `json:"saltLength,omitempty"`
+	// KeyLength is the derived key size in bytes. bcrypt always uses 23.
+	KeyLength int `json:"keyLength,omitempty"`
+}
+
+// DefaultParams returns the recommended parameters of algo, following the
+// OWASP password storage guidance and RFC 9106 for Argon2.
+func DefaultParams(algo Algorithm) (Params, error) {
+	switch algo {
+	case Bcrypt:
+		return Params{Cost: 12}, nil
+	case Scrypt:
+		return Params{LogN: 17, BlockSize: 8, Parallelism: 1, SaltLength: 16, KeyLength: 32}, nil
+	case Argon2i, Argon2id:
+		return Params{Time: 3, Memory: 64 * 1024, Parallelism: 4, SaltLength: 16, KeyLength: 32}, nil
+	case PBKDF2SHA256:
+		return Params{Iterations: 600000, SaltLength: 16, KeyLength: 32}, nil
+	case PBKDF2SHA512:
+		return Params{Iterations: 210000, SaltLength: 16, KeyLength: 64}, nil
+	default:
+		return Params{}, fmt.Errorf("unknown password hash algorithm: %s", algo)
+	}
+}
+
+// Hash is a parsed password hash.
+type Hash struct {
+	Algorithm Algorithm `json:"algorithm"`
+	Params    Params    `json:"params"`
+	Salt      []byte    `json:"salt,omitempty"`
+	Key       []byte    `json:"key,omitempty"`
+
+	encoded string
+}
+
+// String returns the encoded form of the hash.
+func (h *Hash) String() string {
+	return h.encoded
+}
+
+// Generate hashes password with a random salt and returns the encoded hash.
+func Generate(password []byte, algo Algorithm, p Params) (string, error) {
+	if algo == Bcrypt {
+		if p.Cost > maxBcryptCost {
+			return "", fmt.Errorf("bcrypt: cost %d exceeds the maximum of %d", p.Cost, maxBcryptCost)
+		}
+		b, err := bcrypt.GenerateFromPassword(password, p.Cost)
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	}
+
+	if err := validate(algo, p); err != nil {
+		return "", err
+	}
+
+	salt := make([]byte, p.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key, err := derive(password, algo, p, salt)
+	if err != nil {
+		return "", err
+	}
+
+	return encode(algo, p, salt, key), nil
+}
+
+// Verify reports whether password matches the encoded hash. Keys are
+// compared in constant time.
+func Verify(password []byte, encoded string) (bool, error) {
+	h, err := Parse(encoded)
+	if err != nil {
+		return false, err
+	}
+
+	if h.Algorithm == Bcrypt {
+		err := bcrypt.CompareHashAndPassword([]byte(encoded), password)
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, nil
+		}
+		return err == nil, err
+	}
+
+	key, err := derive(password, h.Algorithm, h.Params, h.Salt)
+	if err != nil {
+		return false, err
+	}
+
+	return subtle.ConstantTimeCompare(key, h.Key) == 1, nil
+}
+
+// NeedsRehash reports whether the encoded hash was produced with a different
+// algorithm or different cost parameters than algo and p.
+func NeedsRehash(encoded string, algo Algorithm, p Params) (bool, error) {
+	h, err := Parse(encoded)
+	if err != nil {
+		return false, err
+	}
+
+	if h.Algorithm != algo {
+		return true, nil
+	}
+
+	if algo == Bcrypt {
+		return h.Params.Cost != p.Cost, nil
+	}
+
+	return h.Params != p, nil
+}
+
+// Parse decodes a bcrypt, scrypt, Argon2 or PBKDF2 hash.
+func Parse(encoded string) (*Hash, error) {
+	if strings.HasPrefix(encoded, "$2a$") || strings.HasPrefix(encoded, "$2b$") || strings.HasPrefix(encoded, "$2y$") {
+		cost, err := bcrypt.Cost([]byte(encoded))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidHash, err)
+		}
+		if cost > maxBcryptCost {
+			return nil, fmt.Errorf("%w: bcrypt cost %d exceeds the maximum of %d", ErrInvalidHash, cost, maxBcryptCost)
+		}
+		return &Hash{Algorithm: Bcrypt, Params: Params{Cost: cost}, encoded: encoded}, nil
+	}
+
+	fields := strings.Split(encoded, "$")
+	if len(fields) < 5 || fields[0] != "" {
+		return nil, ErrInvalidHash
+	}
+
+	algo := Algorithm(fields[1])
+	switch algo {
+	case Argon2i, Argon2id:
+		return parseArgon2(algo, fields, encoded)
+	case Scrypt, PBKDF2SHA256, PBKDF2SHA512:
+		if len(fields) != 5 {
+			return nil, ErrInvalidHash
+		}
+	default:
+		return nil, fmt.Errorf("%w: unknown algorithm %q", ErrInvalidHash, fields[1])
+	}
+
+	// passlib stores a bare iteration count and uses adapted base64.
+	passlib := algo != Scrypt && !strings.Contains(fields[2], "=")
+	if passlib {
+		fields[3], fields[4] = ab64(fields[3]), ab64(fields[4])
+	}
+
+	salt, key, err := decodeSaltKey(fields[3], fields[4])
+	if err != nil {
+		return nil, err
+	}
+
+	h := &Hash{Algorithm: algo, Salt: salt, Key: key, encoded: encoded}
+	h.Params.SaltLength = len(salt)
+	h.Params.KeyLength = len(key)
+
+	if passlib {
+		n, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return nil, ErrInvalidHash
+		}
+		h.Params.Iterations = n
+		return h, validate(algo, h.Params)
+	}
+
+	params, err := parseParams(fields[2])
+	if err != nil {
+		return nil, err
+	}
+
+	if algo == Scrypt {
+		h.Params.LogN = params["ln"]
+		h.Params.BlockSize = params["r"]
+		h.Params.Parallelism = params["p"]
+	} else {
+		h.Params.Iterations = params["i"]
+	}
+
+	return h, validate(algo, h.Params)
+}
+
+func parseArgon2(algo Algorithm, fields []string, encoded string) (*Hash, error) {
+	if len(fields) != 6 {
+		return nil, ErrInvalidHash
+	}
+
+	version, err := parseParams(fields[2])
+	if err != nil {
+		return nil, err
+	}
+	if version["v"] != argon2Version {
+		return nil, fmt.Errorf("%w: unsupported argon2 version %d", ErrInvalidHash, version["v"])
+	}
+
+	params, err := parseParams(fields[3])
+	if err != nil {
+		return nil, err
+	}
+
+	// Larger values would wrap around in the uint32 fields.
+	if params["t"] > math.MaxUint32 || params["m"] > math.MaxUint32 {
+		return nil, fmt.Errorf("%w: argon2 parameters out of range", ErrInvalidHash)
+	}
+
+	salt, key, err := decodeSaltKey(fields[4], fields[5])
+	if err != nil {
+		return nil, err
+	}
+
+	h := &Hash{
+		Algorithm: algo,
+		Params: Params{
+			Time:        uint32(params["t"]),
+			Memory:      uint32(params["m"]),
+			Parallelism: params["p"],
+			SaltLength:  len(salt),
+			KeyLength:   len(key),
+		},
+		Salt:    salt,
+		Key:     key,
+		encoded: encoded,
+	}
+
+	return h, validate(algo, h.Params)
+}
+
+// parseParams parses a comma separated list of name=integer pairs.
+func parseParams(s string) (map[string]int, error) {
+	params := make(map[string]int)
+	for _, pair := range strings.Split(s, ",") {
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, ErrInvalidHash
+		}
+
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return nil, ErrInvalidHash
+		}
+		params[name] = n
+	}
+
+	return params, nil
+}
+
+func decodeSaltKey(salt, key string) ([]byte, []byte, error) {
+	s, err := base64.RawStdEncoding.DecodeString(salt)
+	if err != nil {
+		return nil, nil, ErrInvalidHash
+	}
+
+	k, err := base64.RawStdEncoding.DecodeString(key)
+	if err != nil || len(k) == 0 {
+		return nil, nil, ErrInvalidHash
+	}
+
+	return s, k, nil
+}
+
+// ab64 converts passlib's adapted base64 alphabet to the standard one.
+func ab64(s string) string {
+	return strings.ReplaceAll(s, ".", "+")
+}
+
+func validate(algo Algorithm, p Params) error {
+	if p.KeyLength <= 0 || p.SaltLength < 0 {
+		return fmt.Errorf("%s: salt and key lengths must be positive", algo)
+	}
+	if p.KeyLength > maxSaltKeyLength || p.SaltLength > maxSaltKeyLength {
+		return fmt.Errorf("%s: salt and key lengths must not exceed %d bytes", algo, maxSaltKeyLength)
+	}
+
+	switch algo {
+	case Scrypt:
+		if p.LogN <= 0 || p.LogN >= 64 || p.BlockSize <= 0 || p.Parallelism <= 0 {
+			return fmt.Errorf("scrypt: invalid parameters ln=%d r=%d p=%d", p.LogN, p.BlockSize, p.Parallelism)
+		}
+		if p.LogN > 24 || p.BlockSize > maxScryptMemory || p.Parallelism > maxScryptWork || uint64(p.BlockSize)<<p.LogN > maxScryptMemory || uint64(p.BlockSize)*uint64(p.Parallelism)<<p.LogN > maxScryptWork {
+			return fmt.Errorf("scrypt: parameters ln=%d r=%d p=%d exceed the maximum cost", p.LogN, p.BlockSize, p.Parallelism)
+		}
+	case Argon2i, Argon2id:
+		if p.Time == 0 || p.Memory == 0 || p.Parallelism <= 0 || p.Parallelism > 255 {
+			return fmt.Errorf("%s: invalid parameters t=%d m=%d p=%d", algo, p.Time, p.Memory, p.Parallelism)
+		}
+		if p.Time > maxArgon2Time || p.Memory > maxArgon2Memory {
+			return fmt.Errorf("%s: parameters t=%d m=%d exceed the maximum of t=%d m=%d", algo, p.Time, p.Memory, maxArgon2Time, maxArgon2Memory)
+		}
+	case PBKDF2SHA256, PBKDF2SHA512:
+		if p.Iterations <= 0 {
+			return fmt.Errorf("%s: iterations must be positive", algo)
+		}
+		if p.Iterations > maxPBKDF2Rounds {
+			return fmt.Errorf("%s: %d iterations exceed the maximum of %d", algo, p.Iterations, maxPBKDF2Rounds)
+		}
+	default:
+		return fmt.Errorf("unknown password hash algorithm: %s", algo)
+	}
+
+	return nil
+}
+
+func derive(password []byte, algo Algorithm, p Params, salt []byte) ([]byte, error) {
+	switch algo {
+	case Scrypt:
+		return scrypt.Key(password, salt, 1<<p.LogN, p.BlockSize, p.Parallelism, p.KeyLength)
+	case Argon2i:
+		return argon2.Key(password, salt, p.Time, p.Memory, uint8(p.Parallelism), uint32(p.KeyLength)), nil
+	case Argon2id:
+		return argon2.IDKey(password, salt, p.Time, p.Memory, uint8(p.Parallelism), uint32(p.KeyLength)), nil
+	case PBKDF2SHA256:
+		return pbkdf2.Key(password, salt, p.Iterations, p.KeyLength, sha256.New), nil
+	case PBKDF2SHA512:
+		return pbkdf2.Key(password, salt, p.Iterations, p.KeyLength, func() hash.Hash { return sha512.New() }), nil
+	default:
+		return nil, fmt.Errorf("unknown password hash algorithm: %s", algo)
+	}
+}
+
+func encode(algo Algorithm, p Params, salt, key []byte) string {
+	b64 := base64.RawStdEncoding
+	var params string
+	switch algo {
+	case Scrypt:
+		params = fmt.Sprintf("ln=%d,r=%d,p=%d", p.LogN, p.BlockSize, p.Parallelism)
+	case Argon2i, Argon2id:
+		params = fmt.Sprintf("v=%d$m=%d,t=%d,p=%d", argon2Version, p.Memory, p.Time, p.Parallelism)
+	default:
+		params = fmt.Sprintf("i=%d,l=%d", p.Iterations, p.KeyLength)
+	}
+
+	return fmt.Sprintf("$%s$%s$%s$%s", algo, params, b64.EncodeToString(salt), b64.EncodeToString(key))
+}
@@ -0,0 +1,152 @@
+package password_test
+
+import (
+	"testing"
+
+	. "github.com/TechMDW/hashit/pkg/hash/password"
+)
+
+// Known hashes of "password" with the salt "saltsaltsaltsalt", computed with
+// Python's hashlib. The bcrypt hash is from the crypt_blowfish test suite.
+var knownHashes = map[string]string{
+	"$2a$05$CCCCCCCCCCCCCCCCCCCCC.E5YPO9kmyuRGyh0XouQYb4YMJKvyOeW":                                                                             "U*U",
+	"$scrypt$ln=4,r=8,p=1$c2FsdHNhbHRzYWx0c2FsdA$5f/Vi+XRWGUNGScbsma6KJ4zLFIke/NJsrvr7lQLAyA":                                                  "password",
+	"$pbkdf2-sha256$i=1000,l=32$c2FsdHNhbHRzYWx0c2FsdA$8nX7hwFEzIB8aPajJTYK8weHQc5Ngz0pFVAKvSu4jQA":                                            "password",
+	"$pbkdf2-sha256$1000$c2FsdHNhbHRzYWx0c2FsdA$8nX7hwFEzIB8aPajJTYK8weHQc5Ngz0pFVAKvSu4jQA":                                                   "password",
+	"$pbkdf2-sha512$i=1000,l=64$c2FsdHNhbHRzYWx0c2FsdA$715rqIr5dXOVPpBhqqsugl037zT5bWJTWYmZtIcK8hBnisKpwfY7kokvwjDrNHqHhF50Pb7MD6HvkJwiDQw4ww": "password",
+}
+
+// cheap returns parameters fast enough for tests.
+func cheap(algo Algorithm) Params {
+	switch algo {
+	case Bcrypt:
+		return Params{Cost: 4}
+	case Scrypt:
+		return Params{LogN: 4, BlockSize: 8, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+	case Argon2i, Argon2id:
+		return Params{Time: 1, Memory: 64, Parallelism: 2, SaltLength: 16, KeyLength: 32}
+	default:
+		return Params{Iterations: 100, SaltLength: 16, KeyLength: 32}
+	}
+}
+
+func TestVerifyKnown(t *testing.T) {
+	for encoded, pw := range knownHashes {
+		ok, err := Verify([]byte(pw), encoded)
+		if err != nil {
+			t.Errorf("Verify(%s): %v", encoded, err)
+			continue
+		}
+		if !ok {
+			t.Errorf("Verify(%s) = false, want true", encoded)
+		}
+
+		ok, _ = Verify([]byte(pw+"x"), encoded)
+		if ok {
+			t.Errorf("Verify(%s) accepted a wrong password", encoded)
+		}
+	}
+}
+
+func TestGenerateVerify(t *testing.T) {
+	for _, algo := range Algorithms {
+		p := cheap(algo)
+		encoded, err := Generate([]byte("hunter2"), algo, p)
+		if err != nil {
+			t.Fatalf("Generate(%s): %v", algo, err)
+		}
+
+		h, err := Parse(encoded)
+		if err != nil {
+			t.Fatalf("Parse(%s): %v", encoded, err)
+		}
+		if h.Algorithm != algo {
+			t.Errorf("Parse(%s).Algorithm = %s, want %s", encoded, h.Algorithm, algo)
+		}
+
+		if ok, err := Verify([]byte("hunter2"), encoded); err != nil || !ok {
+			t.Errorf("Verify(%s) = %v, %v; want true", encoded, ok, err)
+		}
+		if ok, _ := Verify([]byte("hunter3"), encoded); ok {
+			t.Errorf("Verify(%s) accepted a wrong password", encoded)
+		}
+
+		if rehash, err := NeedsRehash(encoded, algo, p); err != nil || rehash {
+			t.Errorf("NeedsRehash(%s) with same params = %v, %v; want false", encoded, rehash, err)
+		}
+	}
+}
+
+func TestNeedsRehash(t *testing.T) {
+	encoded, err := Generate([]byte("hunter2"), PBKDF2SHA256, cheap(PBKDF2SHA256))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stronger := cheap(PBKDF2SHA256)
+	stronger.Iterations *= 2
+	if rehash, _ := NeedsRehash(encoded, PBKDF2SHA256, stronger); !rehash {
+		t.Error("NeedsRehash with more iterations = false, want true")
+	}
+	if rehash, _ := NeedsRehash(encoded, Argon2id, cheap(Argon2id)); !rehash {
+		t.Error("NeedsRehash with another algorithm = false, want true")
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, encoded := range []string{
+		"",
+		"password",
+		"$md5$abc$def",
+		"$argon2id$v=16$m=64,t=1,p=1$c2FsdA$a2V5",
+		"$argon2id$v=19$m=64,t=0,p=1$c2FsdA$a2V5",
+		"$scrypt$ln=x,r=8,p=1$c2FsdA$a2V5",
+		"$pbkdf2-sha256$i=1000,l=32$c2FsdA$",
+	} {
+		if _, err := Parse(encoded); err == nil {
+			t.Errorf("Parse(%q) succeeded, want error", encoded)
+		}
+	}
+}
+
+func TestParseOversizedParams(t *testing.T) {
+	for _, encoded := range []string{
+		"$argon2id$v=19$m=4294967295,t=1,p=1$c2FsdA$a2V5",
+		"$argon2id$v=19$m=4294967360,t=1,p=1$c2FsdA$a2V5",
+		"$argon2id$v=19$m=65536,t=4294967295,p=1$c2FsdA$a2V5",
+		"$scrypt$ln=63,r=8,p=1$c2FsdA$a2V5",
+		"$scrypt$ln=20,r=64,p=1$c2FsdA$a2V5",
+		"$scrypt$ln=17,r=8,p=1000$c2FsdA$a2V5",
+		"$scrypt$ln=1,r=9223372036854775807,p=9223372036854775807$c2FsdA$a2V5",
+		"$pbkdf2-sha256$i=2147483647,l=32$c2FsdA$a2V5",
+		"$pbkdf2-sha512$2147483647$c2FsdA$a2V5",
+		"$2a$31$N9qo8uLOickgx2ZMRZoMyeIjZAgcfl7p92ldGxad68LJZdL17lhWy",
+		"$2b$19$N9qo8uLOickgx2ZMRZoMyeIjZAgcfl7p92ldGxad68LJZdL17lhWy",
+	} {
+		if _, err := Parse(encoded); err == nil {
+			t.Errorf("Parse(%q) succeeded, want error", encoded)
+		}
+		if _, err := Verify([]byte("password"), encoded); err == nil {
+			t.Errorf("Verify(%q) succeeded, want error", encoded)
+		}
+		if _, err := NeedsRehash(encoded, Bcrypt, Params{Cost: 12}); err == nil {
+			t.Errorf("NeedsRehash(%q) succeeded, want error", encoded)
+		}
+	}
+
+	if _, err := Generate([]byte("password"), Bcrypt, Params{Cost: 31}); err == nil {
+		t.Error("Generate with bcrypt cost 31 succeeded, want error")
+	}
+
+	// The recommended parameters stay well within the bounds.
+	for _, encoded := range []string{
+		"$argon2id$v=19$m=2097152,t=1,p=4$c2FsdA$a2V5",
+		"$scrypt$ln=20,r=8,p=1$c2FsdA$a2V5",
+		"$pbkdf2-sha256$i=600000,l=32$c2FsdA$a2V5",
+		"$2a$12$N9qo8uLOickgx2ZMRZoMyeIjZAgcfl7p92ldGxad68LJZdL17lhWy",
+	} {
+		if _, err := Parse(encoded); err != nil {
+			t.Errorf("Parse(%q) failed: %v", encoded, err)
+		}
+	}
+}
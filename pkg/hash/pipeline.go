@@ -0,0 +1,141 @@
+package hash
+
+import (
+	"context"
+	"errors"
+	"hash"
+	"io"
+	"sync"
+)
+
+// pipeline feeds the same ordered stream of chunks to a set of hashers. Each
+// hasher is owned by a single long-lived worker goroutine, so writes to a
+// hasher never overlap and always arrive in submission order.
+type pipeline struct {
+	workers []chan []byte
+	pending sync.WaitGroup
+}
+
+func newPipeline(hashers []hash.Hash) *pipeline {
+	p := &pipeline{
+		workers: make([]chan []byte, len(hashers)),
+	}
+
+	for i, h := range hashers {
+		ch := make(chan []byte, 1)
+		p.workers[i] = ch
+
+		go func(h hash.Hash) {
+			for chunk := range ch {
+				h.Write(chunk)
+				p.pending.Done()
+			}
+		}(h)
+	}
+
+	return p
+}
+
+// submit queues chunk for every hasher. The caller must not modify chunk
+// until wait returns.
+func (p *pipeline) submit(chunk []byte) {
+	p.pending.Add(len(p.workers))
+	for _, ch := range p.workers {
+		ch <- chunk
+	}
+}
+
+// wait blocks until every submitted chunk has been written to every hasher.
+func (p *pipeline) wait() {
+	p.pending.Wait()
+}
+
+// close waits for outstanding chunks and stops the workers.
+func (p *pipeline) close() {
+	p.wait()
+	for _, ch := range p.workers {
+		close(ch)
+	}
+}
+
+// writeBytes feeds b to all hashers in BufferSize chunks. The data is not
+// copied, so chunks can be queued back to back. It stops between chunks
+// with a CanceledError once ctx is done.
+func writeBytes(ctx context.Context, b []byte, hashers []hash.Hash) error {
+	p := newPipeline(hashers)
+	defer p.close()
+
+	for i := 0; i < len(b); i += BufferSize {
+		// Queued chunks are still written by close, so i bytes are hashed.
+		if err := canceled(ctx, int64(i)); err != nil {
+			return err
+		}
+
+		end := min(i+BufferSize, len(b))
+		p.submit(b[i:end])
+	}
+
+	return nil
+}
+
+// writeReader feeds everything read from r to all hashers. Reads are double
+// buffered: the next chunk is read while the hashers work on the current one.
+// It stops between chunks with a CanceledError once ctx is done, and reports
+// progress to the observer of ctx, if any.
+func writeReader(ctx context.Context, r io.Reader, hashers []hash.Hash) error {
+	_, err := feedReader(ctx, r, hashers, nil)
+	return err
+}
+
+// feedReader is writeReader calling afterChunk, unless nil, each time a
+// chunk was written to every hasher. The hashers are idle during the call,
+// so their state can be saved. It returns the number of bytes hashed, even
+// when it fails.
+func feedReader(ctx context.Context, r io.Reader, hashers []hash.Hash, afterChunk func(processed int64) error) (int64, error) {
+	p := newPipeline(hashers)
+	defer p.close()
+
+	tracker := newProgressTracker(ctx, r)
+
+	bufs := [2][]byte{make([]byte, BufferSize), make([]byte, BufferSize)}
+	cur := 0
+	var processed int64
+
+	n, err := readChunk(r, bufs[cur])
+	for n > 0 && err == nil {
+		if err := canceled(ctx, processed); err != nil {
+			return processed, err
+		}
+
+		p.submit(bufs[cur][:n])
+
+		cur ^= 1
+		next, nextErr := readChunk(r, bufs[cur])
+
+		p.wait()
+		processed += int64(n)
+		tracker.update(processed)
+		if afterChunk != nil {
+			if err := afterChunk(processed); err != nil {
+				return processed, err
+			}
+		}
+		n, err = next, nextErr
+	}
+	if err != nil {
+		return processed, err
+	}
+
+	tracker.done(processed)
+	return processed, nil
+}
+
+// readChunk fills buf from r. It returns a nil error at the end of the input.
+func readChunk(r io.Reader, buf []byte) (int, error) {
+	n, err := io.ReadFull(r, buf)
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return n, nil
+	}
+
+	return n, err
+}
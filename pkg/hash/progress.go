@@ -0,0 +1,176 @@
+package hash
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"time"
+)
+
+// Progress is a report on the input hashed so far.
+type Progress struct {
+	// Processed is the number of input bytes hashed so far.
+	Processed int64 `json:"processed"`
+	// Total is the size of the input, or -1 when it is unknown, e.g. for a
+	// pipe.
+	Total int64 `json:"total"`
+	// Elapsed is the time since hashing started.
+	Elapsed time.Duration `json:"elapsedNs"`
+	// Rate is the mean throughput in bytes per second.
+	Rate float64 `json:"bytesPerSec"`
+	// ETA is the estimated time left, or -1 when Total is unknown.
+	ETA time.Duration `json:"etaNs"`
+	// Done is set on the last report, once the whole input was hashed.
+	Done bool `json:"done"`
+}
+
+// Percent returns how much of the input was hashed, from 0 to 100, or -1
+// when the size of the input is unknown.
+func (p Progress) Percent() float64 {
+	switch {
+	case p.Total < 0:
+		return -1
+	case p.Total == 0:
+		return 100
+	default:
+		return min(100, 100*float64(p.Processed)/float64(p.Total))
+	}
+}
+
+// ProgressObserver receives progress reports while an input is hashed.
+// Progress is called from the hashing goroutine, so it should return
+// quickly.
+type ProgressObserver interface {
+	Progress(p Progress)
+}
+
+// ProgressFunc adapts a function to a ProgressObserver.
+type ProgressFunc func(p Progress)
+
+func (f ProgressFunc) Progress(p Progress) {
+	f(p)
+}
+
+type progressKey struct{}
+
+type progressConfig struct {
+	observer ProgressObserver
+	interval time.Duration
+}
+
+// WithProgress returns a copy of ctx that makes HashFile, HasherMultiFile
+// and the functions hashing an io.Reader report their progress to observer,
+// at most once per interval and once more when they are done. An interval
+// of zero reports after every chunk of BufferSize bytes. Functions that hash
+// several files at once, such as HashFiles and HashTree, do not report
+// progress.
+func WithProgress(ctx context.Context, observer ProgressObserver, interval time.Duration) context.Context {
+	return context.WithValue(ctx, progressKey{}, &progressConfig{observer: observer, interval: interval})
+}
+
+// withoutProgress returns a copy of ctx without a progress observer.
+func withoutProgress(ctx context.Context) context.Context {
+	return context.WithValue(ctx, progressKey{}, (*progressConfig)(nil))
+}
+
+// progressTracker sends the reports of one input to the observer of a
+// context. A nil tracker reports nothing.
+type progressTracker struct {
+	progressConfig
+	total int64
+	start time.Time
+	last  time.Time
+}
+
+// newProgressTracker returns a tracker for hashing r, or nil when ctx has no
+// progress observer.
+func newProgressTracker(ctx context.Context, r io.Reader) *progressTracker {
+	cfg, _ := ctx.Value(progressKey{}).(*progressConfig)
+	if cfg == nil || cfg.observer == nil {
+		return nil
+	}
+
+	now := time.Now()
+	return &progressTracker{progressConfig: *cfg, total: inputSize(r), start: now, last: now}
+}
+
+// update reports processed bytes once the interval since the last report
+// has passed.
+func (t *progressTracker) update(processed int64) {
+	if t == nil {
+		return
+	}
+
+	now := time.Now()
+	if now.Sub(t.last) < t.interval {
+		return
+	}
+	t.last = now
+	t.observer.Progress(t.progress(processed, now, false))
+}
+
+// done sends the last report.
+func (t *progressTracker) done(processed int64) {
+	if t == nil {
+		return
+	}
+
+	t.observer.Progress(t.progress(processed, time.Now(), true))
+}
+
+func (t *progressTracker) progress(processed int64, now time.Time, done bool) Progress {
+	p := Progress{
+		Processed: processed,
+		Total:     t.total,
+		Elapsed:   now.Sub(t.start),
+		ETA:       -1,
+		Done:      done,
+	}
+	if done && p.Total >= 0 {
+		p.Total = processed
+	}
+
+	if secs := p.Elapsed.Seconds(); secs > 0 {
+		p.Rate = float64(processed) / secs
+	}
+
+	switch {
+	case done:
+		p.ETA = 0
+	case p.Total >= 0 && p.Rate > 0:
+		left := max(0, p.Total-processed)
+		p.ETA = time.Duration(float64(left) / p.Rate * float64(time.Second))
+	}
+
+	return p
+}
+
+// inputSize returns the number of bytes left in r when r is a regular file
+// or another io.Seeker, and -1 otherwise.
+func inputSize(r io.Reader) int64 {
+	if f, ok := r.(interface{ Stat() (fs.FileInfo, error) }); ok {
+		info, err := f.Stat()
+		if err != nil || !info.Mode().IsRegular() {
+			return -1
+		}
+	}
+
+	s, ok := r.(io.Seeker)
+	if !ok {
+		return -1
+	}
+
+	cur, err := s.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return -1
+	}
+	end, err := s.Seek(0, io.SeekEnd)
+	if err != nil {
+		return -1
+	}
+	if _, err := s.Seek(cur, io.SeekStart); err != nil {
+		return -1
+	}
+
+	return end - cur
+}
@@ -0,0 +1,108 @@
+package hash_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/iotest"
+
+	. "github.com/TechMDW/hashit/pkg/hash"
+)
+
+// recordProgress returns a context reporting after every chunk and the
+// slice the reports are appended to.
+func recordProgress() (context.Context, *[]Progress) {
+	var reports []Progress
+	ctx := WithProgress(context.Background(), ProgressFunc(func(p Progress) {
+		reports = append(reports, p)
+	}), 0)
+	return ctx, &reports
+}
+
+func TestHasherMultiFileProgress(t *testing.T) {
+	size := 2*BufferSize + 12345
+	filePath := filepath.Join(t.TempDir(), "large")
+	if err := os.WriteFile(filePath, largeData(size), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	ctx, reports := recordProgress()
+	if _, err := HasherMultiFile(ctx, filePath, "sha256"); err != nil {
+		t.Fatalf("HasherMultiFile failed: %v", err)
+	}
+
+	expected := []int64{BufferSize, 2 * BufferSize, int64(size), int64(size)}
+	if len(*reports) != len(expected) {
+		t.Fatalf("Expected %d reports, got %d", len(expected), len(*reports))
+	}
+	for i, p := range *reports {
+		if p.Processed != expected[i] || p.Total != int64(size) {
+			t.Errorf("Report %d: expected %d of %d bytes, got %d of %d", i, expected[i], size, p.Processed, p.Total)
+		}
+		if done := i == len(expected)-1; p.Done != done {
+			t.Errorf("Report %d: expected Done %v", i, done)
+		}
+		if p.ETA < 0 {
+			t.Errorf("Report %d: expected an ETA, got %v", i, p.ETA)
+		}
+	}
+
+	last := (*reports)[len(*reports)-1]
+	if last.Percent() != 100 || last.ETA != 0 {
+		t.Errorf("Expected the last report at 100%% with no time left, got %.1f%% and %v", last.Percent(), last.ETA)
+	}
+}
+
+func TestHashFileProgress(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "test")
+	if err := os.WriteFile(filePath, []byte("test data"), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	algo, err := Lookup("md5")
+	if err != nil {
+		t.Fatalf("Lookup failed: %v", err)
+	}
+
+	ctx, reports := recordProgress()
+	if _, err := HashFile(ctx, filePath, algo.New()); err != nil {
+		t.Fatalf("HashFile failed: %v", err)
+	}
+
+	if len(*reports) == 0 || !(*reports)[len(*reports)-1].Done {
+		t.Fatalf("Expected a final report, got %+v", *reports)
+	}
+}
+
+func TestHashReaderProgressUnknownSize(t *testing.T) {
+	ctx, reports := recordProgress()
+	r := iotest.HalfReader(bytes.NewReader(largeData(BufferSize + 1)))
+	if _, err := HashReader(ctx, r, "md5"); err != nil {
+		t.Fatalf("HashReader failed: %v", err)
+	}
+
+	for _, p := range *reports {
+		if p.Total != -1 || p.Percent() != -1 || (!p.Done && p.ETA != -1) {
+			t.Errorf("Expected an unknown size and ETA, got %+v", p)
+		}
+	}
+}
+
+func TestHashFilesNoProgress(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "test")
+	if err := os.WriteFile(filePath, []byte("test data"), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	ctx, reports := recordProgress()
+	files := []FileEntry{{Path: "test", OSPath: filePath}}
+	if _, err := HashFiles(ctx, files, []string{"md5"}, 1); err != nil {
+		t.Fatalf("HashFiles failed: %v", err)
+	}
+
+	if len(*reports) != 0 {
+		t.Errorf("Expected no reports from HashFiles, got %d", len(*reports))
+	}
+}
@@ -0,0 +1,75 @@
+package hash
+
+import (
+	"context"
+	"fmt"
+	"hash"
+	"io"
+	"time"
+)
+
+// HashReader hashes everything read from r with the named algorithms,
+// reading r only once. Selectors are resolved as by SelectAlgorithms; when
+// none are given every registered algorithm is used. Hashing stops with a
+// CanceledError once ctx is done.
+func HashReader(ctx context.Context, r io.Reader, algos ...string) (Hashes, error) {
+	timeStart := time.Now()
+
+	selected, err := resolveAlgorithms(algos)
+	if err != nil {
+		return Hashes{}, err
+	}
+
+	hashers, hashes := initializeHashers(selected)
+	if err := writeReader(ctx, r, hashers); err != nil {
+		return Hashes{}, err
+	}
+
+	setHashes(hashes, hashers)
+	timeSince := time.Since(timeStart)
+	hashes.Duration = timeSince.Milliseconds()
+	hashes.DurationStr = timeSince.String()
+	return *hashes, nil
+}
+
+// ComputeHashReader returns a hash of everything read from r using the
+// specified hash type.
+func ComputeHashReader(ctx context.Context, r io.Reader, hashType string) (*GenericHash, error) {
+	algo, err := lookup(hashType)
+	if err != nil {
+		return &GenericHash{}, err
+	}
+
+	return hashReader(ctx, r, algo.Name, algo.New())
+}
+
+// ComputeHashSeedReader returns a hash of everything read from r using the
+// specified hash type initialized with seed.
+func ComputeHashSeedReader(ctx context.Context, r io.Reader, hashType string, seed uint64) (*GenericHash, error) {
+	name, h, err := newSeededHasher(hashType, seed)
+	if err != nil {
+		return &GenericHash{}, err
+	}
+
+	return hashReader(ctx, r, name, h)
+}
+
+// hashReader writes everything read from r to h and returns the result
+// labeled with the algorithm name.
+func hashReader(ctx context.Context, r io.Reader, name string, h hash.Hash) (*GenericHash, error) {
+	timeStart := time.Now()
+
+	if err := writeReader(ctx, r, []hash.Hash{h}); err != nil {
+		return nil, err
+	}
+
+	gh := &GenericHash{Algorithm: name}
+	gh.HashBytes = h.Sum(nil)
+	gh.HexDigest = fmt.Sprintf("%x", gh.HashBytes)
+	gh.Size = len(gh.HashBytes)
+	timeSince := time.Since(timeStart)
+	gh.Duration = timeSince.Milliseconds()
+	gh.DurationStr = timeSince.String()
+
+	return gh, nil
+}
@@ -0,0 +1,80 @@
+package hash_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	. "github.com/TechMDW/hashit/pkg/hash"
+)
+
+func TestHashReader(t *testing.T) {
+	hashes, err := HashReader(context.Background(), iotest.OneByteReader(strings.NewReader("test data")))
+	if err != nil {
+		t.Fatalf("HashReader failed: %v", err)
+	}
+
+	compareHashes(t, hashes, expectedHashes)
+}
+
+func TestHashReaderSubset(t *testing.T) {
+	hashes, err := HashReader(context.Background(), strings.NewReader("test data"), "sha256", "MD5", "crc32c")
+	if err != nil {
+		t.Fatalf("HashReader failed: %v", err)
+	}
+
+	expected := []HasherArray{
+		{Type: "sha256", Hash: expectedHashes.SHA2.SHA256},
+		{Type: "md5", Hash: expectedHashes.MD5},
+		{Type: "crc32_Castagnoli", Hash: expectedHashes.CRC.CRC32Castagnoli},
+	}
+
+	arr := hashes.Array()
+	if len(arr) != len(expected) {
+		t.Fatalf("Expected %d hashes, got %d", len(expected), len(arr))
+	}
+	for i := range expected {
+		if arr[i] != expected[i] {
+			t.Errorf("Expected %+v at %d, got %+v", expected[i], i, arr[i])
+		}
+	}
+
+	if hashes.SHA1 != "" {
+		t.Errorf("Expected SHA1 to be empty, got %s", hashes.SHA1)
+	}
+}
+
+func TestHashReaderUnknown(t *testing.T) {
+	_, err := HashReader(context.Background(), strings.NewReader("test data"), "nope")
+	if err == nil {
+		t.Fatal("Expected error for unknown hash type, got nil")
+	}
+}
+
+func TestHashReaderCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := HashReader(ctx, bytes.NewReader(largeData(2*BufferSize)), "sha256")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestComputeHashReader(t *testing.T) {
+	for hashType, expected := range expectedHashesMap {
+		t.Run(hashType, func(t *testing.T) {
+			genericHash, err := ComputeHashReader(context.Background(), strings.NewReader("test data"), hashType)
+			if err != nil {
+				t.Fatalf("ComputeHashReader failed for %s: %v", hashType, err)
+			}
+
+			if genericHash.HexDigest != expected {
+				t.Errorf("Expected %s hash %s, got %s", hashType, expected, genericHash.HexDigest)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package hash
+
+import (
+	"fmt"
+	"hash"
+	"strings"
+	"sync"
+)
+
+// Algorithm describes a hash function known to the registry.
+type Algorithm struct {
+	// Name is the canonical, lowercase name used with ComputeHash and -t.
+	Name string `json:"name"`
+	// Aliases are alternative names that resolve to the same algorithm.
+	Aliases []string `json:"aliases,omitempty"`
+	// Family groups related algorithms, e.g. "sha2" or "crc".
+	Family string `json:"family"`
+	// Size is the digest size in bytes.
+	Size int `json:"size"`
+	// BlockSize is the underlying block size in bytes.
+	BlockSize int `json:"blockSize"`
+	// Security is the security status of the algorithm. Register takes it
+	// from the family when left empty.
+	Security Security `json:"security"`
+	// SecurityNote briefly explains the security status, e.g. the known
+	// attacks on a broken algorithm.
+	SecurityNote string `json:"securityNote,omitempty"`
+	// New returns a fresh hash.Hash for the algorithm.
+	New func() hash.Hash `json:"-"`
+	// NewKeyed returns a keyed instance (a MAC) of the algorithm for
+	// ComputeHMAC. It is nil for algorithms without a keyed mode.
+	NewKeyed func(key []byte) (hash.Hash, error) `json:"-"`
+	// NewSeeded returns an instance of the algorithm with the given seed for
+	// ComputeHashSeed. It is nil for algorithms that take no seed.
+	NewSeeded func(seed uint64) (hash.Hash, error) `json:"-"`
+	// NewXOF returns the algorithm as an extendable-output function with
+	// the cSHAKE function name and customization strings, for ComputeXOF.
+	// It is nil for fixed-size algorithms.
+	NewXOF func(functionName, customization []byte) (XOF, error) `json:"-"`
+
+	// label is the name printed for the digest in Hashes output when it
+	// differs from Name, so that text and JSON output keep the casing
+	// hashit has always used, e.g. "crc32_IEEE".
+	label string
+	// field points at the slot in Hashes that holds the digest of a
+	// built-in algorithm. Algorithms added with Register have none and are
+	// reported in Hashes.Extra instead.
+	field func(*Hashes) *string
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   []*Algorithm
+	names      = map[string]*Algorithm{}
+)
+
+// Register adds an algorithm to the registry so it can be used by
+// ComputeHash, HasherMulti and HasherMultiFile. Size and BlockSize are taken
+// from the hash returned by New when left zero, and Security from the family
+// of a built-in algorithm when left empty.
+func Register(a Algorithm) error {
+	return register(&a)
+}
+
+func register(a *Algorithm) error {
+	if a.New == nil {
+		return fmt.Errorf("hash %q: New must not be nil", a.Name)
+	}
+
+	a.Name = normalizeName(a.Name)
+	if a.Name == "" {
+		return fmt.Errorf("hash name must not be empty")
+	}
+
+	aliases := make([]string, 0, len(a.Aliases))
+	for _, alias := range a.Aliases {
+		aliases = append(aliases, normalizeName(alias))
+	}
+	a.Aliases = aliases
+
+	if a.Size == 0 || a.BlockSize == 0 {
+		h := a.New()
+		if a.Size == 0 {
+			a.Size = h.Size()
+		}
+		if a.BlockSize == 0 {
+			a.BlockSize = h.BlockSize()
+		}
+	}
+
+	if a.Security == "" {
+		if s, ok := familySecurity[a.Family]; ok {
+			a.Security, a.SecurityNote = s.status, s.note
+		} else {
+			a.Security = SecurityUnknown
+		}
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	for _, name := range append([]string{a.Name}, a.Aliases...) {
+		if _, ok := names[name]; ok {
+			return fmt.Errorf("hash %q is already registered", name)
+		}
+	}
+
+	registry = append(registry, a)
+	names[a.Name] = a
+	for _, alias := range a.Aliases {
+		names[alias] = a
+	}
+
+	return nil
+}
+
+func mustRegister(a *Algorithm) {
+	if err := register(a); err != nil {
+		panic(err)
+	}
+}
+
+// Lookup returns the algorithm registered under name or one of its aliases.
+// Names are matched case-insensitively. CRCs of the reveng catalogue and
+// custom CRC parameters are found as well, see package crc.
+func Lookup(name string) (Algorithm, error) {
+	a, err := lookup(name)
+	if err != nil {
+		return Algorithm{}, err
+	}
+
+	return *a, nil
+}
+
+func lookup(name string) (*Algorithm, error) {
+	registryMu.RLock()
+	a, ok := names[normalizeName(name)]
+	registryMu.RUnlock()
+	if ok {
+		return a, nil
+	}
+
+	return lookupCRC(name)
+}
+
+// Algorithms returns all registered algorithms in registration order.
+func Algorithms() []Algorithm {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	algos := make([]Algorithm, len(registry))
+	for i, a := range registry {
+		algos[i] = *a
+	}
+
+	return algos
+}
+
+func algorithms() []*Algorithm {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	return append([]*Algorithm(nil), registry...)
+}
+
+// displayName returns the name of the algorithm in Hashes output.
+func (a *Algorithm) displayName() string {
+	if a.label != "" {
+		return a.label
+	}
+
+	return a.Name
+}
+
+func normalizeName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
@@ -0,0 +1,148 @@
+package hash_test
+
+import (
+	"context"
+	"encoding/json"
+	"hash"
+	"hash/crc32"
+	"testing"
+
+	. "github.com/TechMDW/hashit/pkg/hash"
+)
+
+func TestLookupAlias(t *testing.T) {
+	tests := map[string]string{
+		"SHA256":      "sha256",
+		"sha-256":     "sha256",
+		"sha512/256":  "sha512_256",
+		"crc32c":      "crc32_castagnoli",
+		" Blake2s ":   "blake2s256",
+		"sha3-256":    "sha3_256",
+		"crc32_IEEE":  "crc32_ieee",
+		"blake2b-512": "blake2b512",
+	}
+
+	for name, expected := range tests {
+		algo, err := Lookup(name)
+		if err != nil {
+			t.Fatalf("Lookup(%q) failed: %v", name, err)
+		}
+		if algo.Name != expected {
+			t.Errorf("Expected Lookup(%q) to return %s, got %s", name, expected, algo.Name)
+		}
+	}
+
+	if _, err := Lookup("nope"); err == nil {
+		t.Error("Expected error for unknown hash type, got nil")
+	}
+}
+
+func TestAlgorithmSizes(t *testing.T) {
+	for _, algo := range Algorithms() {
+		h := algo.New()
+		if algo.Size != h.Size() {
+			t.Errorf("Expected %s size %d, got %d", algo.Name, h.Size(), algo.Size)
+		}
+		if algo.BlockSize != h.BlockSize() {
+			t.Errorf("Expected %s block size %d, got %d", algo.Name, h.BlockSize(), algo.BlockSize)
+		}
+	}
+}
+
+func TestRegister(t *testing.T) {
+	koopman := crc32.MakeTable(crc32.Koopman)
+	err := Register(Algorithm{
+		Name:    "test_crc32_koopman",
+		Aliases: []string{"test-koopman"},
+		Family:  "test",
+		New:     func() hash.Hash { return crc32.New(koopman) },
+	})
+	if err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	err = Register(Algorithm{Name: "test-koopman", New: func() hash.Hash { return crc32.NewIEEE() }})
+	if err == nil {
+		t.Error("Expected error when registering a duplicate name, got nil")
+	}
+
+	gh, err := ComputeHash(context.Background(), []byte("test data"), "TEST-KOOPMAN", false)
+	if err != nil {
+		t.Fatalf("ComputeHash failed: %v", err)
+	}
+	if gh.HexDigest != expectedHashes.CRC.CRC32Koopman {
+		t.Errorf("Expected hash %s, got %s", expectedHashes.CRC.CRC32Koopman, gh.HexDigest)
+	}
+
+	hashes, err := HasherMulti(context.Background(), []byte("test data"))
+	if err != nil {
+		t.Fatalf("HasherMulti failed: %v", err)
+	}
+	if hashes.Extra["test_crc32_koopman"] != expectedHashes.CRC.CRC32Koopman {
+		t.Errorf("Expected extra hash %s, got %s", expectedHashes.CRC.CRC32Koopman, hashes.Extra["test_crc32_koopman"])
+	}
+
+	arr := hashes.Array()
+	last := arr[len(arr)-1]
+	if last.Type != "test_crc32_koopman" || last.Hash != expectedHashes.CRC.CRC32Koopman {
+		t.Errorf("Expected registered hash at the end of Array, got %+v", last)
+	}
+}
+
+func TestAlgorithmSecurity(t *testing.T) {
+	tests := map[string]Security{
+		"md5":           SecurityBroken,
+		"sha1":          SecurityBroken,
+		"ripemd160":     SecurityLegacy,
+		"sha256":        SecuritySecure,
+		"blake3":        SecuritySecure,
+		"crc32":         SecurityNonCryptographic,
+		"crc-16/xmodem": SecurityNonCryptographic,
+		"xxh3":          SecurityNonCryptographic,
+	}
+
+	for name, expected := range tests {
+		algo, err := Lookup(name)
+		if err != nil {
+			t.Fatalf("Lookup(%q) failed: %v", name, err)
+		}
+		if algo.Security != expected {
+			t.Errorf("Expected %s to be %s, got %s", name, expected, algo.Security)
+		}
+		if crypto := expected != SecurityNonCryptographic; algo.Cryptographic() != crypto {
+			t.Errorf("Expected %s Cryptographic() to be %t", name, crypto)
+		}
+	}
+
+	for _, algo := range Algorithms() {
+		if algo.Security == "" {
+			t.Errorf("%s has no security status", algo.Name)
+		}
+	}
+
+	if err := Register(Algorithm{Name: "test_unknown", Family: "test", New: func() hash.Hash { return crc32.NewIEEE() }}); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	if algo, _ := Lookup("test_unknown"); algo.Security != SecurityUnknown {
+		t.Errorf("Expected registered algorithm to be %s, got %s", SecurityUnknown, algo.Security)
+	}
+}
+
+func TestAlgorithmJSON(t *testing.T) {
+	tests := map[string]string{
+		"md5":      `{"name":"md5","family":"md","size":16,"blockSize":64,"security":"broken","securityNote":"practical collision attacks","cryptographic":true,"keyed":true,"seeded":false,"xof":false}`,
+		"shake256": `{"name":"shake256","aliases":["cshake256"],"family":"sha3","size":64,"blockSize":136,"security":"secure","cryptographic":true,"keyed":true,"seeded":false,"xof":true}`,
+		"xxh64":    `{"name":"xxh64","aliases":["xxhash64","xxhash"],"family":"xxhash","size":8,"blockSize":32,"security":"non-cryptographic","securityNote":"fast hash","cryptographic":false,"keyed":false,"seeded":true,"xof":false}`,
+	}
+
+	for name, expected := range tests {
+		algo, _ := Lookup(name)
+		j, err := json.Marshal(algo)
+		if err != nil {
+			t.Fatalf("Marshal(%s) failed: %v", name, err)
+		}
+		if string(j) != expected {
+			t.Errorf("Expected %s JSON\n%s\ngot\n%s", name, expected, j)
+		}
+	}
+}
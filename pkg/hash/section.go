@@ -0,0 +1,216 @@
+package hash
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"time"
+)
+
+// Range selects the region of a file to hash. The zero Range selects the
+// whole file.
+type Range struct {
+	// Offset is the first byte hashed.
+	Offset int64 `json:"offset"`
+	// Length is the number of bytes hashed. Zero hashes everything up to
+	// the end, less SkipTail.
+	Length int64 `json:"length"`
+	// SkipTail leaves out this many bytes at the end of the file, e.g. a
+	// trailing signature.
+	SkipTail int64 `json:"skipTail,omitempty"`
+}
+
+// Section returns the part of r selected by rg, where size is the size of
+// r. A range that reaches past the end is an error rather than truncated.
+func (rg Range) Section(r io.ReaderAt, size int64) (*io.SectionReader, error) {
+	if rg.Offset < 0 || rg.Length < 0 || rg.SkipTail < 0 {
+		return nil, fmt.Errorf("invalid range: offset, length and skipped tail must not be negative")
+	}
+
+	end := size - rg.SkipTail
+	if rg.Offset > end {
+		return nil, fmt.Errorf("range starts at byte %d, past the end at byte %d", rg.Offset, max(end, 0))
+	}
+
+	length := end - rg.Offset
+	if rg.Length > 0 {
+		if rg.Length > length {
+			return nil, fmt.Errorf("range of %d bytes at byte %d reaches past the end at byte %d", rg.Length, rg.Offset, end)
+		}
+		length = rg.Length
+	}
+
+	return io.NewSectionReader(r, rg.Offset, length), nil
+}
+
+// FileSection is a region of an open file, selected by a Range.
+type FileSection struct {
+	*io.SectionReader
+	file *os.File
+}
+
+// Close closes the file.
+func (s *FileSection) Close() error {
+	return s.file.Close()
+}
+
+// OpenSection opens the file at path and returns the region selected by rg.
+// The file must be seekable; block devices are sized by seeking to their
+// end.
+func OpenSection(path string, rg Range) (*FileSection, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	size, err := seekSize(file)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("%s: cannot hash a range: %w", path, err)
+	}
+
+	section, err := rg.Section(file, size)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return &FileSection{SectionReader: section, file: file}, nil
+}
+
+// openSection opens the file at path for hashing the region rg. The zero
+// Range reads the file as it is, so that pipes and other files without a
+// size can still be hashed whole.
+func openSection(path string, rg Range) (io.ReadCloser, error) {
+	if rg == (Range{}) {
+		return os.Open(path)
+	}
+
+	return OpenSection(path, rg)
+}
+
+// seekSize returns the size of f by seeking to its end, which unlike Stat
+// also works for block devices such as disk partitions.
+func seekSize(f *os.File) (int64, error) {
+	size, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return 0, err
+	}
+
+	return size, nil
+}
+
+// HashFileRange returns a hash of the region rg of the file, as HashFile
+// does for the whole file.
+func HashFileRange(ctx context.Context, path string, rg Range, h hash.Hash) (*GenericHash, error) {
+	timeStart := time.Now()
+
+	gh := &GenericHash{
+		Input: []byte(path),
+	}
+
+	r, err := openSection(path, rg)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	if err := writeReader(ctx, r, []hash.Hash{h}); err != nil {
+		return nil, err
+	}
+
+	gh.HashBytes = h.Sum(nil)
+	gh.HexDigest = fmt.Sprintf("%x", gh.HashBytes)
+	gh.Size = len(gh.HashBytes)
+	timeSince := time.Since(timeStart)
+	gh.Duration = timeSince.Milliseconds()
+	gh.DurationStr = timeSince.String()
+
+	return gh, nil
+}
+
+// HasherMultiFileRange hashes the region rg of the file with the selected
+// algorithms, as HasherMultiFile does for the whole file.
+func HasherMultiFileRange(ctx context.Context, path string, rg Range, algos ...string) (Hashes, error) {
+	timeStart := time.Now()
+
+	selected, err := resolveAlgorithms(algos)
+	if err != nil {
+		return Hashes{}, err
+	}
+	hashers, hashes := initializeHashers(selected)
+
+	r, err := openSection(path, rg)
+	if err != nil {
+		return Hashes{}, err
+	}
+	defer r.Close()
+
+	if err := writeReader(ctx, r, hashers); err != nil {
+		return Hashes{}, err
+	}
+
+	setHashes(hashes, hashers)
+	timeSince := time.Since(timeStart)
+	hashes.Duration = timeSince.Milliseconds()
+	hashes.DurationStr = timeSince.String()
+	return *hashes, nil
+}
+
+// DefaultFingerprintSize is the size of the head and of the tail hashed by
+// FingerprintFile unless another is given.
+const DefaultFingerprintSize = 1 << 20
+
+// FingerprintFile returns a quick fingerprint of the file for cheap change
+// detection: the named hash of its first n bytes, its last n bytes and its
+// size as a 64-bit big-endian integer. Files of up to 2n bytes are hashed
+// whole, followed by the size. Only fingerprints made with the same hash
+// type and n can be compared, and changes in the middle of larger files go
+// unnoticed.
+func FingerprintFile(ctx context.Context, path, hashType string, n int64) (*GenericHash, error) {
+	timeStart := time.Now()
+
+	if n <= 0 {
+		return &GenericHash{}, fmt.Errorf("invalid fingerprint size %d", n)
+	}
+	algo, err := lookup(hashType)
+	if err != nil {
+		return &GenericHash{}, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	size, err := seekSize(file)
+	if err != nil {
+		return nil, err
+	}
+
+	h := algo.New()
+	head := io.NewSectionReader(file, 0, min(n, size))
+	tailStart := max(n, size-n)
+	tail := io.NewSectionReader(file, min(tailStart, size), max(0, size-tailStart))
+	if err := writeReader(withoutProgress(ctx), io.MultiReader(head, tail), []hash.Hash{h}); err != nil {
+		return nil, err
+	}
+	binary.Write(h, binary.BigEndian, uint64(size))
+
+	gh := &GenericHash{Input: []byte(path), Algorithm: algo.Name}
+	gh.HashBytes = h.Sum(nil)
+	gh.HexDigest = fmt.Sprintf("%x", gh.HashBytes)
+	gh.Size = len(gh.HashBytes)
+	timeSince := time.Since(timeStart)
+	gh.Duration = timeSince.Milliseconds()
+	gh.DurationStr = timeSince.String()
+
+	return gh, nil
+}
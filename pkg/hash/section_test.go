@@ -0,0 +1,154 @@
+package hash_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/TechMDW/hashit/pkg/hash"
+)
+
+func TestRangeSection(t *testing.T) {
+	data := []byte("header|payload|signature")
+
+	tests := []struct {
+		name     string
+		rg       Range
+		expected string
+		wantErr  bool
+	}{
+		{name: "zero", rg: Range{}, expected: string(data)},
+		{name: "offset", rg: Range{Offset: 7}, expected: "payload|signature"},
+		{name: "length", rg: Range{Length: 6}, expected: "header"},
+		{name: "skip tail", rg: Range{SkipTail: 10}, expected: "header|payload"},
+		{name: "all", rg: Range{Offset: 7, Length: 7, SkipTail: 10}, expected: "payload"},
+		{name: "empty at end", rg: Range{Offset: int64(len(data))}, expected: ""},
+		{name: "offset past end", rg: Range{Offset: 25}, wantErr: true},
+		{name: "length past skipped tail", rg: Range{Offset: 7, Length: 8, SkipTail: 10}, wantErr: true},
+		{name: "tail larger than file", rg: Range{SkipTail: 100}, wantErr: true},
+		{name: "negative", rg: Range{Offset: -1}, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			section, err := test.rg.Section(bytes.NewReader(data), int64(len(data)))
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("Expected an error for %+v", test.rg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := make([]byte, section.Size())
+			if _, err := section.ReadAt(got, 0); err != nil && len(got) > 0 {
+				t.Fatal(err)
+			}
+			if string(got) != test.expected {
+				t.Errorf("Expected %q, got %q", test.expected, got)
+			}
+		})
+	}
+}
+
+func TestHashFileRange(t *testing.T) {
+	data := largeData(3*BufferSize + 123)
+	filePath := filepath.Join(t.TempDir(), "image.bin")
+	if err := os.WriteFile(filePath, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	rg := Range{Offset: BufferSize - 7, SkipTail: 1000}
+	want := sha256.Sum256(data[rg.Offset : len(data)-int(rg.SkipTail)])
+
+	gh, err := HashFileRange(context.Background(), filePath, rg, sha256.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gh.HexDigest != hex.EncodeToString(want[:]) {
+		t.Errorf("Expected %x, got %s", want, gh.HexDigest)
+	}
+
+	hashes, err := HasherMultiFileRange(context.Background(), filePath, rg, "sha256", "md5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hashes.SHA2.SHA256 != hex.EncodeToString(want[:]) {
+		t.Errorf("Expected %x, got %s", want, hashes.SHA2.SHA256)
+	}
+
+	if _, err := HashFileRange(context.Background(), filePath, Range{Offset: int64(len(data)) + 1}, sha256.New()); err == nil {
+		t.Error("Expected an error for a range past the end of the file")
+	}
+}
+
+func TestFingerprintFile(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, data []byte) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	fingerprint := func(path string, n int64) string {
+		gh, err := FingerprintFile(context.Background(), path, "sha256", n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return gh.HexDigest
+	}
+
+	data := largeData(10000)
+	original := write("original", data)
+
+	t.Run("layout", func(t *testing.T) {
+		h := sha256.New()
+		h.Write(data[:100])
+		h.Write(data[len(data)-100:])
+		binary.Write(h, binary.BigEndian, uint64(len(data)))
+		if got, want := fingerprint(original, 100), hex.EncodeToString(h.Sum(nil)); got != want {
+			t.Errorf("Expected %s, got %s", want, got)
+		}
+	})
+
+	t.Run("small file hashed whole", func(t *testing.T) {
+		h := sha256.New()
+		h.Write(data)
+		binary.Write(h, binary.BigEndian, uint64(len(data)))
+		if got, want := fingerprint(original, 6000), hex.EncodeToString(h.Sum(nil)); got != want {
+			t.Errorf("Expected %s, got %s", want, got)
+		}
+	})
+
+	t.Run("changes", func(t *testing.T) {
+		base := fingerprint(original, 100)
+
+		tail := bytes.Clone(data)
+		tail[len(tail)-1] ^= 1
+		if fingerprint(write("tail", tail), 100) == base {
+			t.Error("Expected a change in the tail to change the fingerprint")
+		}
+
+		if fingerprint(write("grown", append(bytes.Clone(data[:5000]), data...)), 100) == base {
+			t.Error("Expected a change in size to change the fingerprint")
+		}
+
+		middle := bytes.Clone(data)
+		middle[5000] ^= 1
+		if fingerprint(write("middle", middle), 100) != base {
+			t.Error("Expected a change in the middle to go unnoticed")
+		}
+	})
+
+	if _, err := FingerprintFile(context.Background(), original, "sha256", 0); err == nil {
+		t.Error("Expected an error for a fingerprint size of 0")
+	}
+}
@@ -0,0 +1,77 @@
+package hash
+
+import "encoding/json"
+
+// Security is the security status of an algorithm.
+type Security string
+
+const (
+	// SecuritySecure marks cryptographic hash functions without known
+	// practical attacks.
+	SecuritySecure Security = "secure"
+	// SecurityLegacy marks cryptographic hash functions without practical
+	// attacks that are superseded and not recommended for new designs.
+	SecurityLegacy Security = "legacy"
+	// SecurityBroken marks hash functions with practical collision attacks.
+	// Their plain digests do not prove that data was not tampered with.
+	SecurityBroken Security = "broken"
+	// SecurityNonCryptographic marks checksums and fast hashes that were
+	// never meant to withstand an adversary.
+	SecurityNonCryptographic Security = "non-cryptographic"
+	// SecurityUnknown marks registered algorithms of unknown families.
+	SecurityUnknown Security = "unknown"
+)
+
+type securityStatus struct {
+	status Security
+	note   string
+}
+
+// familySecurity gives the security status of the algorithms of a family.
+// Register uses it for algorithms that do not set their own.
+var familySecurity = map[string]securityStatus{
+	"adler":     {SecurityNonCryptographic, "checksum"},
+	"md":        {SecurityBroken, "practical collision attacks"},
+	"sha1":      {SecurityBroken, "practical chosen-prefix collision attacks"},
+	"sha2":      {SecuritySecure, ""},
+	"sha3":      {SecuritySecure, ""},
+	"ripemd":    {SecurityLegacy, "160-bit digest gives only 80-bit collision resistance"},
+	"whirlpool": {SecuritySecure, ""},
+	"tiger":     {SecurityLegacy, "little recent analysis; not recommended for new designs"},
+	"sm3":       {SecuritySecure, ""},
+	"streebog":  {SecuritySecure, ""},
+	"fnv":       {SecurityNonCryptographic, "fast hash"},
+	"crc":       {SecurityNonCryptographic, "error-detecting code"},
+	"blake":     {SecuritySecure, ""},
+	"xxhash":    {SecurityNonCryptographic, "fast hash"},
+	"checksum":  {SecurityNonCryptographic, "checksum"},
+}
+
+// Cryptographic reports whether the algorithm is a cryptographic hash
+// function, even a broken one.
+func (a Algorithm) Cryptographic() bool {
+	switch a.Security {
+	case SecuritySecure, SecurityLegacy, SecurityBroken:
+		return true
+	}
+	return false
+}
+
+// MarshalJSON encodes the algorithm with whether it is cryptographic and
+// which of the keyed, seeded and extendable-output modes it supports.
+func (a Algorithm) MarshalJSON() ([]byte, error) {
+	type algorithm Algorithm
+	return json.Marshal(struct {
+		algorithm
+		Cryptographic bool `json:"cryptographic"`
+		Keyed         bool `json:"keyed"`
+		Seeded        bool `json:"seeded"`
+		XOF           bool `json:"xof"`
+	}{
+		algorithm:     algorithm(a),
+		Cryptographic: a.Cryptographic(),
+		Keyed:         a.NewKeyed != nil,
+		Seeded:        a.NewSeeded != nil,
+		XOF:           a.NewXOF != nil,
+	})
+}
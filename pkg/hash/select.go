@@ -0,0 +1,93 @@
+package hash
+
+import (
+	"fmt"
+	"strings"
+)
+
+// groups maps selector names to the families they include, in addition to
+// the family names themselves.
+var groups = map[string][]string{
+	// fast selects the non-cryptographic checksums.
+	"fast": {"adler", "fnv", "crc", "xxhash", "checksum"},
+}
+
+// SelectAlgorithms resolves algorithm selectors in order. Each selector is a
+// comma separated list of algorithm names or aliases, family names such as
+// "sha2" or "crc", the group "fast" (non-cryptographic checksums) or "all".
+// Names take precedence over families, duplicates are dropped and no
+// selectors select every registered algorithm.
+func SelectAlgorithms(selectors ...string) ([]Algorithm, error) {
+	selected, err := resolveAlgorithms(selectors)
+	if err != nil {
+		return nil, err
+	}
+
+	algos := make([]Algorithm, len(selected))
+	for i, a := range selected {
+		algos[i] = *a
+	}
+
+	return algos, nil
+}
+
+// resolveAlgorithms expands selectors as described for SelectAlgorithms.
+func resolveAlgorithms(selectors []string) ([]*Algorithm, error) {
+	var algos []*Algorithm
+	seen := make(map[*Algorithm]bool)
+	add := func(a *Algorithm) {
+		if !seen[a] {
+			seen[a] = true
+			algos = append(algos, a)
+		}
+	}
+
+	all := algorithms()
+	for _, selector := range selectors {
+		for _, name := range strings.Split(selector, ",") {
+			name = normalizeName(name)
+			if name == "" {
+				continue
+			}
+
+			if a, err := lookup(name); err == nil {
+				add(a)
+				continue
+			}
+
+			if name == "all" {
+				for _, a := range all {
+					add(a)
+				}
+				continue
+			}
+
+			families, ok := groups[name]
+			if !ok {
+				families = []string{name}
+			}
+
+			found := false
+			for _, a := range all {
+				for _, family := range families {
+					if a.Family == family {
+						add(a)
+						found = true
+					}
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("unknown hash type: %s", name)
+			}
+		}
+	}
+
+	if len(selectors) == 0 {
+		return all, nil
+	}
+	if len(algos) == 0 {
+		return nil, fmt.Errorf("no hash type selected")
+	}
+
+	return algos, nil
+}
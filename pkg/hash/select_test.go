@@ -0,0 +1,102 @@
+package hash_test
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	. "github.com/TechMDW/hashit/pkg/hash"
+)
+
+func selectedNames(t *testing.T, selectors ...string) []string {
+	t.Helper()
+
+	algos, err := SelectAlgorithms(selectors...)
+	if err != nil {
+		t.Fatalf("SelectAlgorithms(%q) failed: %v", selectors, err)
+	}
+
+	names := make([]string, len(algos))
+	for i, a := range algos {
+		names[i] = a.Name
+	}
+	return names
+}
+
+func TestSelectAlgorithms(t *testing.T) {
+	tests := map[string][]string{
+		"sha256,blake2b512,crc32c": {"sha256", "blake2b512", "crc32_castagnoli"},
+		"sha2":                     {"sha224", "sha256", "sha384", "sha512", "sha512_224", "sha512_256"},
+		"md5, sha1,md5":            {"md5", "sha1"},
+		"sha1":                     {"sha1"},
+		"blake2b":                  {"blake2b512"},
+		"SHA3":                     {"sha3_224", "sha3_256", "sha3_384", "sha3_512", "shake128", "shake256", "keccak256", "keccak512"},
+	}
+
+	for selector, expected := range tests {
+		if names := selectedNames(t, selector); !reflect.DeepEqual(names, expected) {
+			t.Errorf("SelectAlgorithms(%q) = %v, expected %v", selector, names, expected)
+		}
+	}
+
+	for _, name := range selectedNames(t, "fast") {
+		a, _ := Lookup(name)
+		if a.Family != "adler" && a.Family != "fnv" && a.Family != "crc" && a.Family != "xxhash" && a.Family != "checksum" {
+			t.Errorf("fast selected %s of family %s", name, a.Family)
+		}
+	}
+
+	if all := selectedNames(t, "all"); len(all) != len(Algorithms()) {
+		t.Errorf("all selected %d algorithms, expected %d", len(all), len(Algorithms()))
+	}
+
+	for _, selector := range []string{"nope", "sha256,nope", ","} {
+		if _, err := SelectAlgorithms(selector); err == nil {
+			t.Errorf("Expected error for selector %q, got nil", selector)
+		}
+	}
+}
+
+func TestHasherMultiSubset(t *testing.T) {
+	hashes, err := HasherMulti(context.Background(), []byte("test data"), "sha256,md5", "crc32")
+	if err != nil {
+		t.Fatalf("HasherMulti failed: %v", err)
+	}
+
+	arr := hashes.Array()
+	expected := []string{"sha256", "md5", "crc32_IEEE"}
+	if len(arr) != len(expected) {
+		t.Fatalf("Expected %d digests, got %d", len(expected), len(arr))
+	}
+	for i, name := range expected {
+		digest := expectedHashesMap[strings.ToLower(name)]
+		if arr[i].Type != name || arr[i].Hash != digest {
+			t.Errorf("Digest %d: got %s %s, expected %s %s", i, arr[i].Type, arr[i].Hash, name, digest)
+		}
+	}
+
+	j, err := json.Marshal(hashes)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if !strings.HasPrefix(string(j), `{"sha256":"`+expectedHashesMap["sha256"]+`","md5":"`) {
+		t.Errorf("Unexpected JSON: %s", j)
+	}
+	if strings.Contains(string(j), "sha2\"") {
+		t.Errorf("Subset JSON contains grouped members: %s", j)
+	}
+
+	full, err := HasherMulti(context.Background(), []byte("test data"))
+	if err != nil {
+		t.Fatalf("HasherMulti failed: %v", err)
+	}
+	j, err = json.Marshal(full)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if !strings.Contains(string(j), `"sha2":{"sha224"`) {
+		t.Errorf("Full JSON is not grouped: %s", j)
+	}
+}
@@ -0,0 +1,148 @@
+package hash
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/TechMDW/hashit/pkg/hash/crc"
+)
+
+// knownAnswers holds the digest of "abc" for every built-in algorithm, taken
+// from the published test vectors where there are some and checked against
+// OpenSSL, libgcrypt, Python and coreutils otherwise.
+var knownAnswers = map[string]string{
+	"adler32":          "024d0127",
+	"md4":              "a448017aaf21d8525fc10ae87aa6729d",
+	"md5":              "900150983cd24fb0d6963f7d28e17f72",
+	"sha1":             "a9993e364706816aba3e25717850c26c9cd0d89d",
+	"sha224":           "23097d223405d8228642a477bda255b32aadbce4bda0b3f7e36c9da7",
+	"sha256":           "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad",
+	"sha384":           "cb00753f45a35e8bb5a03d699ac65007272c32ab0eded1631a8b605a43ff5bed8086072ba1e7cc2358baeca134c825a7",
+	"sha512":           "ddaf35a193617abacc417349ae20413112e6fa4e89a97ea20a9eeee64b55d39a2192992a274fc1a836ba3c23a3feebbd454d4423643ce80e2a9ac94fa54ca49f",
+	"sha512_224":       "4634270f707b6a54daae7530460842e20e37ed265ceee9a43e8924aa",
+	"sha512_256":       "53048e2681941ef99b2e29b76b4c7dabe4c2d0c634fc6d46e0e2f13107e7af23",
+	"sha3_224":         "e642824c3f8cf24ad09234ee7d3c766fc9a3a5168d0c94ad73b46fdf",
+	"sha3_256":         "3a985da74fe225b2045c172d6bd390bd855f086e3e9d525b46bfe24511431532",
+	"sha3_384":         "ec01498288516fc926459f58e2c6ad8df9b473cb0fc08c2596da7cf0e49be4b298d88cea927ac7f539f1edf228376d25",
+	"sha3_512":         "b751850b1a57168a5693cd924b6b096e08f621827444f70d884f5d0240d2712e10e116e9192af3c91a7ec57647e3934057340b4cf408d5a56592f8274eec53f0",
+	"shake128":         "5881092dd818bf5cf8a3ddb793fbcba74097d5c526a6d35f97b83351940f2cc8",
+	"shake256":         "483366601360a8771c6863080cc4114d8db44530f8f1e1ee4f94ea37e78b5739d5a15bef186a5386c75744c0527e1faa9f8726e462a12a4feb06bd8801e751e4",
+	"keccak256":        "4e03657aea45a94fc7d47ba826c8d667c0d1e6e33a64a036ec44f58fa12d6c45",
+	"keccak512":        "18587dc2ea106b9a1563e32b3312421ca164c7f1f07bc922a9c83d77cea3a1e5d0c69910739025372dc14ac9642629379540c17e2a65b19d77aa511a9d00bb96",
+	"ripemd160":        "8eb208f7e05d987a9b044a8e98c6b087f15a0bfc",
+	"hash160":          "bb1be98c142444d7a56aa3981c3942a978e4dc33",
+	"whirlpool":        "4e2448a4c6f486bb16b6562c73b4020bf3043e3a731bce721ae1b303d97e6d4c7181eebdb6c57e277d0e34957114cbd6c797fc9d95d8b582d225292076d4eef5",
+	"tiger":            "2aab1484e8c158f2bfb8c5ff41b57a525129131c957b5f93",
+	"tiger2":           "f68d7bc5af4b43a06e048d7829560d4a9415658bb0b1f3bf",
+	"sm3":              "66c7f0f462eeedd9d1f2d46bdc10e4e24167c4875cf2f7a2297da02b8f4ba8e0",
+	"streebog256":      "4e2919cf137ed41ec4fb6270c61826cc4fffb660341e0af3688cd0626d23b481",
+	"streebog512":      "28156e28317da7c98f4fe2bed6b542d0dab85bb224445fcedaf75d46e26d7eb8d5997f3e0915dd6b7f0aab08d9c8beb0d8c64bae2ab8b3c8c6bc53b3bf0db728",
+	"fnv32":            "439c2f4b",
+	"fnv32a":           "1a47e90b",
+	"fnv64":            "d8dcca186bafadcb",
+	"fnv64a":           "e71fa2190541574b",
+	"crc32_ieee":       "352441c2",
+	"crc32_koopman":    "ba2322ac",
+	"crc32_castagnoli": "364b3fb7",
+	"crc64_iso":        "3776c42000000000",
+	"crc64_ecma":       "2cd8094a1a277627",
+	"blake2b256":       "bddd813c634239723171ef3fee98579b94964e3bb1cb3e427262c8c068d52319",
+	"blake2b384":       "6f56a82c8e7ef526dfe182eb5212f7db9df1317e57815dbda46083fc30f54ee6c66ba83be64b302d7cba6ce15bb556f4",
+	"blake2b512":       "ba80a53f981c4d0d6a2797b69f12f6e94c212f14685ac4b74b12bb6fdbffa2d17d87c5392aab792dc252d5de4533cc9518d38aa8dbf1925ab92386edd4009923",
+	"blake2s256":       "508c5e8c327c14e2e1a72ba34eeb452f37458b209ed63a294d999b4c86675982",
+	"blake3":           "6437b3ac38465133ffb63b75273a8db548c558465d79db03fd359c6cd5bd9d85",
+	"xxh32":            "32d153ff",
+	"xxh64":            "44bc2cf5ad770999",
+	"xxh3":             "78af5f94892f3950",
+	"xxh128":           "06b05ab6733a618578af5f94892f3950",
+	"cksum":            "48aa78a2",
+	"sum_bsd":          "40ac",
+	"sum_sysv":         "0126",
+	"fletcher16":       "4c27",
+	"fletcher32":       "c52562c4",
+	"fletcher64":       "0063626100636261",
+	"inet_checksum":    "3b9d",
+}
+
+// SelfTestResult is the outcome of the self-test of one algorithm.
+type SelfTestResult struct {
+	Algorithm string `json:"algorithm"`
+	Passed    bool   `json:"passed"`
+	// Skipped is set when there is no known answer for the algorithm. Its
+	// streaming behaviour is still tested.
+	Skipped bool   `json:"skipped,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// SelfTest runs the known-answer tests of every registered algorithm and of
+// every CRC of the catalogue. Each algorithm must also give the same digest
+// when its input is written in pieces and after Reset. It stops with a
+// CanceledError once ctx is done, returning the results so far.
+func SelfTest(ctx context.Context) ([]SelfTestResult, error) {
+	var results []SelfTestResult
+
+	for _, a := range algorithms() {
+		if err := canceled(ctx, 0); err != nil {
+			return results, err
+		}
+
+		res := SelfTestResult{Algorithm: a.Name}
+		expected, ok := knownAnswers[a.Name]
+		if err := selfTest(a, expected); err != nil {
+			res.Error = err.Error()
+		} else {
+			res.Passed = true
+			res.Skipped = !ok
+		}
+		results = append(results, res)
+	}
+
+	for _, p := range crc.Catalogue {
+		if err := canceled(ctx, 0); err != nil {
+			return results, err
+		}
+
+		res := SelfTestResult{Algorithm: strings.ToLower(p.Name)}
+		if got := crc.Checksum(p, []byte("123456789")); got != p.Check {
+			res.Error = fmt.Sprintf("check value %#x, expected %#x", got, p.Check)
+		} else {
+			res.Passed = true
+		}
+		results = append(results, res)
+	}
+
+	return results, nil
+}
+
+func selfTest(a *Algorithm, expected string) error {
+	h := a.New()
+	h.Write([]byte("abc"))
+	if got := fmt.Sprintf("%x", h.Sum(nil)); expected != "" && got != expected {
+		return fmt.Errorf("known answer %s, expected %s", got, expected)
+	}
+
+	data := make([]byte, 1000)
+	for i := range data {
+		data[i] = byte(i * 7)
+	}
+
+	h.Reset()
+	h.Write(data)
+	oneShot := h.Sum(nil)
+
+	h.Reset()
+	for i := 0; i < len(data); i += 7 {
+		h.Write(data[i:min(i+7, len(data))])
+	}
+	if !bytes.Equal(h.Sum(nil), oneShot) {
+		return fmt.Errorf("digest of data written in pieces differs")
+	}
+
+	if h.Size() != a.Size || len(oneShot) != a.Size {
+		return fmt.Errorf("digest is %d bytes, expected %d", len(oneShot), a.Size)
+	}
+
+	return nil
+}
@@ -0,0 +1,24 @@
+package hash_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/TechMDW/hashit/pkg/hash"
+)
+
+func TestSelfTest(t *testing.T) {
+	results, err := SelfTest(context.Background())
+	if err != nil {
+		t.Fatalf("SelfTest failed: %v", err)
+	}
+	if len(results) < len(Algorithms()) {
+		t.Fatalf("Expected at least %d results, got %d", len(Algorithms()), len(results))
+	}
+
+	for _, res := range results {
+		if !res.Passed {
+			t.Errorf("%s failed: %s", res.Algorithm, res.Error)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package hash
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var sizeUnits = []struct {
+	suffix string
+	shift  uint
+}{
+	{"g", 30}, {"m", 20}, {"k", 10},
+}
+
+// ParseSize parses a size in bytes, optionally with a K, M or G suffix for
+// KiB, MiB and GiB, e.g. "512", "64K", "1MiB" or "2GB". Both KB and KiB
+// stand for 1024 bytes.
+func ParseSize(size string) (int64, error) {
+	s := strings.ToLower(strings.TrimSpace(size))
+	s = strings.TrimSuffix(strings.TrimSuffix(s, "b"), "i")
+
+	var shift uint
+	for _, u := range sizeUnits {
+		if strings.HasSuffix(s, u.suffix) {
+			s, shift = strings.TrimSuffix(s, u.suffix), u.shift
+			break
+		}
+	}
+
+	n, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+	if err != nil || n < 0 || n > (1<<63-1)>>shift {
+		return 0, fmt.Errorf("invalid size %q", size)
+	}
+
+	return n << shift, nil
+}
+
+// FormatSize formats a size in bytes with the largest unit that divides it,
+// e.g. "64 B", "16 KiB" or "1 MiB".
+func FormatSize(n int64) string {
+	for _, u := range sizeUnits {
+		if n != 0 && n%(1<<u.shift) == 0 {
+			return fmt.Sprintf("%d %siB", n>>u.shift, strings.ToUpper(u.suffix))
+		}
+	}
+
+	return fmt.Sprintf("%d B", n)
+}
@@ -0,0 +1,52 @@
+package hash_test
+
+import (
+	"testing"
+
+	. "github.com/TechMDW/hashit/pkg/hash"
+)
+
+func TestParseSize(t *testing.T) {
+	tests := map[string]int64{
+		"0":     0,
+		"512":   512,
+		"512b":  512,
+		"64K":   64 << 10,
+		"64KiB": 64 << 10,
+		"64kb":  64 << 10,
+		"1M":    1 << 20,
+		" 2GB ": 2 << 30,
+	}
+
+	for s, expected := range tests {
+		n, err := ParseSize(s)
+		if err != nil || n != expected {
+			t.Errorf("ParseSize(%q) = %d, %v, expected %d", s, n, err, expected)
+		}
+	}
+
+	for _, s := range []string{"", "K", "-1", "1.5M", "1T", "9999999999999G"} {
+		if _, err := ParseSize(s); err == nil {
+			t.Errorf("Expected error for %q, got nil", s)
+		}
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	tests := map[int64]string{
+		0:         "0 B",
+		64:        "64 B",
+		1000:      "1000 B",
+		1024:      "1 KiB",
+		64 << 10:  "64 KiB",
+		1 << 20:   "1 MiB",
+		3 << 30:   "3 GiB",
+		1<<20 + 1: "1048577 B",
+	}
+
+	for n, expected := range tests {
+		if s := FormatSize(n); s != expected {
+			t.Errorf("FormatSize(%d) = %q, expected %q", n, s, expected)
+		}
+	}
+}
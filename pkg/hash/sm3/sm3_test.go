@@ -0,0 +1,42 @@
+package sm3_test
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	. "github.com/TechMDW/hashit/pkg/hash/sm3"
+)
+
+func TestVectors(t *testing.T) {
+	// The first two are the examples of GB/T 32905-2016, appendix A.
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"abc", "66c7f0f462eeedd9d1f2d46bdc10e4e24167c4875cf2f7a2297da02b8f4ba8e0"},
+		{strings.Repeat("abcd", 16), "debe9ff92275b8a138604889c18e5a4d6fdb70e5387e5765293dcba39c0c5732"},
+		{"", "1ab21d8355cfa17f8e61194831e81a8f22bec8c728fefb747ed035eb5082aa2b"},
+		{strings.Repeat("a", 1000000), "c8aaf89429554029e231941a2acc0ad61ff2a5acd8fadd25847a3a732b3b02c3"},
+	}
+
+	for _, tt := range tests {
+		sum := Sum([]byte(tt.input))
+		if got := hex.EncodeToString(sum[:]); got != tt.expected {
+			t.Errorf("SM3 of %d bytes = %s, expected %s", len(tt.input), got, tt.expected)
+		}
+	}
+}
+
+func TestStreaming(t *testing.T) {
+	data := []byte(strings.Repeat("abcd", 40))
+	expected := Sum(data)
+
+	h := New()
+	for i := 0; i < len(data); i += 7 {
+		h.Write(data[i:min(i+7, len(data))])
+	}
+	if got := h.Sum(nil); string(got) != string(expected[:]) {
+		t.Errorf("chunked SM3 = %x, expected %x", got, expected)
+	}
+}
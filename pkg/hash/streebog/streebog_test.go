@@ -0,0 +1,70 @@
+package streebog_test
+
+import (
+	"encoding/hex"
+	"hash"
+	"strings"
+	"testing"
+
+	. "github.com/TechMDW/hashit/pkg/hash/streebog"
+)
+
+// m1 is the message M1 of RFC 6986, section 10.1, in input byte order.
+const m1 = "012345678901234567890123456789012345678901234567890123456789012"
+
+func sum(h hash.Hash, s string) string {
+	h.Write([]byte(s))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func TestVectors(t *testing.T) {
+	tests := []struct {
+		input            string
+		size256, size512 string
+	}{
+		{
+			m1,
+			"9d151eefd8590b89daa6ba6cb74af9275dd051026bb149a452fd84e5e57b5500",
+			"1b54d01a4af5b9d5cc3d86d68d285462b19abc2475222f35c085122be4ba1ffa00ad30f8767b3a82384c6574f024c311e2a481332b08ef7f41797891c1646f48",
+		},
+		{
+			"",
+			"3f539a213e97c802cc229d474c6aa32a825a360b2a933a949fd925208d9ce1bb",
+			"8e945da209aa869f0455928529bcae4679e9873ab707b55315f56ceb98bef0a7362f715528356ee83cda5f2aac4c6ad2ba3a715c1bcd81cb8e9f90bf4c1c1a8a",
+		},
+		{
+			"test data",
+			"a7a23a156990adf879a7009bc518df2f31956ecf8d29edd8d837387cdfcd2cfd",
+			"ac6ea199422d1cdd07602be57daf13973e147821d690226ea16ff74ad6dd99b7804d922299107a79fa89bf97f01d9d406cdb87d37507710a41ba1f0d7d2a224c",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := sum(New256(), tt.input); got != tt.size256 {
+			t.Errorf("Streebog-256(%q) = %s, expected %s", tt.input, got, tt.size256)
+		}
+		if got := sum(New512(), tt.input); got != tt.size512 {
+			t.Errorf("Streebog-512(%q) = %s, expected %s", tt.input, got, tt.size512)
+		}
+	}
+}
+
+func TestLongInput(t *testing.T) {
+	expected := "841af1a0b2f92a800fb1b7e4aabc8e48763153c448a0fc57c90ba830e130f152"
+	if got := sum(New256(), strings.Repeat("a", 1000000)); got != expected {
+		t.Errorf("Streebog-256 of a million a = %s, expected %s", got, expected)
+	}
+}
+
+func TestStreaming(t *testing.T) {
+	data := strings.Repeat(m1, 5)
+	expected := sum(New512(), data)
+
+	h := New512()
+	for i := 0; i < len(data); i += 7 {
+		h.Write([]byte(data[i:min(i+7, len(data))]))
+	}
+	if got := hex.EncodeToString(h.Sum(nil)); got != expected {
+		t.Errorf("chunked Streebog-512 = %s, expected %s", got, expected)
+	}
+}
@@ -0,0 +1,263 @@
+package hash
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash"
+
+	"github.com/TechMDW/hashit/pkg/hash/crc"
+)
+
+// cksumParams is the CRC of POSIX cksum before the length is appended.
+var cksumParams, _ = crc.Lookup("CRC-32/CKSUM")
+
+// cksum is the POSIX cksum checksum: a CRC-32 over the data followed by its
+// length in as few little-endian bytes as needed.
+type cksum struct {
+	crc *crc.Hasher
+	n   uint64
+}
+
+// NewCksum returns the checksum of POSIX cksum. Sum32 gives the value
+// printed by cksum.
+func NewCksum() hash.Hash32 {
+	return &cksum{crc: crc.New(cksumParams)}
+}
+
+func (c *cksum) Write(p []byte) (int, error) {
+	c.n += uint64(len(p))
+	return c.crc.Write(p)
+}
+
+func (c *cksum) Sum32() uint32 {
+	h := *c.crc
+	for n := c.n; n > 0; n >>= 8 {
+		h.Write([]byte{byte(n)})
+	}
+	return uint32(h.Sum64())
+}
+
+func (c *cksum) Sum(b []byte) []byte {
+	return binary.BigEndian.AppendUint32(b, c.Sum32())
+}
+
+func (c *cksum) Reset() {
+	c.crc.Reset()
+	c.n = 0
+}
+
+func (c *cksum) Size() int      { return 4 }
+func (c *cksum) BlockSize() int { return 1 }
+
+// bsdSum is the 16-bit rotating checksum of BSD sum and sum -r.
+type bsdSum uint16
+
+// NewBSDSum returns the checksum of BSD sum, printed by GNU sum -r.
+func NewBSDSum() hash.Hash {
+	return new(bsdSum)
+}
+
+func (s *bsdSum) Write(p []byte) (int, error) {
+	sum := *s
+	for _, b := range p {
+		sum = (sum>>1 | sum<<15) + bsdSum(b)
+	}
+	*s = sum
+	return len(p), nil
+}
+
+func (s *bsdSum) Sum(b []byte) []byte {
+	return binary.BigEndian.AppendUint16(b, uint16(*s))
+}
+
+func (s *bsdSum) Reset()         { *s = 0 }
+func (s *bsdSum) Size() int      { return 2 }
+func (s *bsdSum) BlockSize() int { return 1 }
+
+// sysvSum is the checksum of System V sum and GNU sum -s: the byte sum
+// folded to 16 bits.
+type sysvSum uint32
+
+// NewSysVSum returns the checksum of System V sum, printed by GNU sum -s.
+func NewSysVSum() hash.Hash {
+	return new(sysvSum)
+}
+
+func (s *sysvSum) Write(p []byte) (int, error) {
+	sum := *s
+	for _, b := range p {
+		sum += sysvSum(b)
+	}
+	*s = sum
+	return len(p), nil
+}
+
+func (s *sysvSum) Sum(b []byte) []byte {
+	r := uint32(*s)&0xffff + uint32(*s)>>16
+	r = r&0xffff + r>>16
+	return binary.BigEndian.AppendUint16(b, uint16(r))
+}
+
+func (s *sysvSum) Reset()         { *s = 0 }
+func (s *sysvSum) Size() int      { return 2 }
+func (s *sysvSum) BlockSize() int { return 1 }
+
+// fletcher implements Fletcher-16, -32 and -64 over little-endian words of
+// size/2 bytes. A trailing partial word is padded with zeros.
+type fletcher struct {
+	size       int
+	mod        uint64
+	sum1, sum2 uint64
+	buf        [4]byte
+	nbuf       int
+}
+
+// NewFletcher16 returns Fletcher-16, which sums bytes modulo 255.
+func NewFletcher16() hash.Hash {
+	return &fletcher{size: 2, mod: 1<<8 - 1}
+}
+
+// NewFletcher32 returns Fletcher-32, which sums little-endian 16-bit words
+// modulo 65535.
+func NewFletcher32() hash.Hash {
+	return &fletcher{size: 4, mod: 1<<16 - 1}
+}
+
+// NewFletcher64 returns Fletcher-64, which sums little-endian 32-bit words
+// modulo 2^32-1.
+func NewFletcher64() hash.Hash {
+	return &fletcher{size: 8, mod: 1<<32 - 1}
+}
+
+func (f *fletcher) Write(p []byte) (int, error) {
+	n := len(p)
+	word := f.size / 2
+
+	for len(p) > 0 {
+		c := copy(f.buf[f.nbuf:word], p)
+		f.nbuf += c
+		p = p[c:]
+		if f.nbuf == word {
+			f.add()
+		}
+	}
+
+	return n, nil
+}
+
+func (f *fletcher) add() {
+	var w uint64
+	for i := f.nbuf - 1; i >= 0; i-- {
+		w = w<<8 | uint64(f.buf[i])
+	}
+	f.sum1 = (f.sum1 + w) % f.mod
+	f.sum2 = (f.sum2 + f.sum1) % f.mod
+	f.nbuf = 0
+}
+
+func (f *fletcher) Sum(b []byte) []byte {
+	d := *f
+	if d.nbuf > 0 {
+		d.add()
+	}
+
+	v := d.sum2<<(4*f.size) | d.sum1
+	for i := f.size - 1; i >= 0; i-- {
+		b = append(b, byte(v>>(8*i)))
+	}
+	return b
+}
+
+func (f *fletcher) Reset() {
+	f.sum1, f.sum2, f.nbuf = 0, 0, 0
+}
+
+func (f *fletcher) Size() int      { return f.size }
+func (f *fletcher) BlockSize() int { return f.size / 2 }
+
+// inetChecksum is the Internet checksum of RFC 1071: the one's complement
+// of the one's complement sum of big-endian 16-bit words.
+type inetChecksum struct {
+	sum uint32
+	odd bool
+	hi  byte
+}
+
+// NewInternetChecksum returns the RFC 1071 Internet checksum used by IP,
+// TCP, UDP and ICMP.
+func NewInternetChecksum() hash.Hash {
+	return new(inetChecksum)
+}
+
+func (c *inetChecksum) Write(p []byte) (int, error) {
+	n := len(p)
+	if c.odd && len(p) > 0 {
+		c.sum += uint32(c.hi)<<8 | uint32(p[0])
+		c.odd = false
+		p = p[1:]
+	}
+	for ; len(p) >= 2; p = p[2:] {
+		c.sum += uint32(p[0])<<8 | uint32(p[1])
+		// Fold early so the sum cannot overflow.
+		if c.sum > 0xffff0000 {
+			c.sum = c.sum&0xffff + c.sum>>16
+		}
+	}
+	if len(p) == 1 {
+		c.hi, c.odd = p[0], true
+	}
+
+	return n, nil
+}
+
+func (c *inetChecksum) Sum(b []byte) []byte {
+	sum := c.sum
+	if c.odd {
+		sum += uint32(c.hi) << 8
+	}
+	for sum>>16 != 0 {
+		sum = sum&0xffff + sum>>16
+	}
+	return binary.BigEndian.AppendUint16(b, ^uint16(sum))
+}
+
+func (c *inetChecksum) Reset()         { *c = inetChecksum{} }
+func (c *inetChecksum) Size() int      { return 2 }
+func (c *inetChecksum) BlockSize() int { return 2 }
+
+// nativeFormats formats the output line of the traditional tool of an
+// algorithm from the digest and the input size.
+var nativeFormats = map[string]func(sum []byte, size int64) string{
+	"cksum": func(sum []byte, size int64) string {
+		return fmt.Sprintf("%d %d", binary.BigEndian.Uint32(sum), size)
+	},
+	"sum_bsd": func(sum []byte, size int64) string {
+		return fmt.Sprintf("%05d %5d", binary.BigEndian.Uint16(sum), (size+1023)/1024)
+	},
+	"sum_sysv": func(sum []byte, size int64) string {
+		return fmt.Sprintf("%d %d", binary.BigEndian.Uint16(sum), (size+511)/512)
+	},
+}
+
+// NativeLine returns the line printed for a file by the traditional tool of
+// the algorithm, including the byte or block count: cksum for "cksum",
+// sum -r for "sum_bsd" and sum -s for "sum_sysv". Other algorithms give a
+// coreutils "<hex>  <path>" line. A path of "-" or "" stands for stdin,
+// which the tools print without a name. The trailing newline is not
+// included.
+func NativeLine(algorithm string, sum []byte, size int64, path string) string {
+	format, ok := nativeFormats[algorithm]
+	if !ok {
+		if path == "" {
+			path = "-"
+		}
+		prefix, path := escapeChecksumPath(path)
+		return fmt.Sprintf("%s%x  %s", prefix, sum, path)
+	}
+
+	line := format(sum, size)
+	if path != "" && path != "-" {
+		line += " " + path
+	}
+	return line
+}
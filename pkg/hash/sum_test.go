@@ -0,0 +1,136 @@
+package hash_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	. "github.com/TechMDW/hashit/pkg/hash"
+)
+
+// TestNativeLine compares with the output of GNU coreutils 9.1 cksum,
+// sum -r and sum -s.
+func TestNativeLine(t *testing.T) {
+	tests := []struct {
+		data     []byte
+		path     string
+		expected map[string]string
+	}{
+		{
+			data: nil,
+			path: "empty",
+			expected: map[string]string{
+				"cksum":    "4294967295 0 empty",
+				"sum_bsd":  "00000     0 empty",
+				"sum_sysv": "0 0 empty",
+			},
+		},
+		{
+			data: []byte("x"),
+			path: "-",
+			expected: map[string]string{
+				"cksum":    "12738659 1",
+				"sum_bsd":  "00120     1",
+				"sum_sysv": "120 1",
+			},
+		},
+		{
+			data: []byte("hello world\n"),
+			path: "a.txt",
+			expected: map[string]string{
+				"cksum":    "3733384285 12 a.txt",
+				"sum_bsd":  "03762     1 a.txt",
+				"sum_sysv": "1126 1 a.txt",
+				"md5":      "6f5902ac237024bdd0c176cb93063dc4  a.txt",
+			},
+		},
+		{
+			data: make([]byte, 3000000),
+			path: "big",
+			expected: map[string]string{
+				"cksum":    "3933251326 3000000 big",
+				"sum_bsd":  "00000  2930 big",
+				"sum_sysv": "0 5860 big",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		for algo, expected := range test.expected {
+			gh, err := ComputeHash(context.Background(), test.data, algo, false)
+			if err != nil {
+				t.Fatalf("ComputeHash(%s) failed: %v", algo, err)
+			}
+
+			if line := NativeLine(algo, gh.HashBytes, int64(len(test.data)), test.path); line != expected {
+				t.Errorf("%s of %d bytes: expected %q, got %q", algo, len(test.data), expected, line)
+			}
+		}
+	}
+
+	if line := NativeLine("md5", []byte{0xab}, 1, ""); line != "ab  -" {
+		t.Errorf("Expected stdin line %q, got %q", "ab  -", line)
+	}
+}
+
+func TestFletcherAndInternetChecksum(t *testing.T) {
+	// Fletcher values from Wikipedia and the example of RFC 1071 section 3.
+	tests := []struct {
+		algo     string
+		data     string
+		expected string
+	}{
+		{"fletcher16", "abcde", "c8f0"},
+		{"fletcher16", "abcdef", "2057"},
+		{"fletcher16", "abcdefgh", "0627"},
+		{"fletcher32", "abcde", "f04fc729"},
+		{"fletcher32", "abcdef", "56502d2a"},
+		{"fletcher32", "abcdefgh", "ebe19591"},
+		{"fletcher64", "abcde", "c8c6c527646362c6"},
+		{"fletcher64", "abcdef", "c8c72b276463c8c6"},
+		{"fletcher64", "abcdefgh", "312e2b28cccac8c6"},
+		{"rfc1071", "\x00\x01\xf2\x03\xf4\xf5\xf6\xf7", "220d"},
+	}
+
+	for _, test := range tests {
+		gh, err := ComputeHash(context.Background(), []byte(test.data), test.algo, false)
+		if err != nil {
+			t.Fatalf("ComputeHash(%s) failed: %v", test.algo, err)
+		}
+		if gh.HexDigest != test.expected {
+			t.Errorf("%s(%q): expected %s, got %s", test.algo, test.data, test.expected, gh.HexDigest)
+		}
+	}
+}
+
+func TestChecksumStreaming(t *testing.T) {
+	data := bytes.Repeat([]byte("The quick brown fox jumps over the lazy dog. "), 100)
+
+	for _, algo := range []string{"cksum", "sum_bsd", "sum_sysv", "fletcher16", "fletcher32", "fletcher64", "inet_checksum"} {
+		a, err := Lookup(algo)
+		if err != nil {
+			t.Fatalf("Lookup(%s) failed: %v", algo, err)
+		}
+
+		whole := a.New()
+		whole.Write(data)
+		expected := fmt.Sprintf("%x", whole.Sum(nil))
+
+		for _, step := range []int{1, 3, 7} {
+			h := a.New()
+			for i := 0; i < len(data); i += step {
+				h.Write(data[i:min(i+step, len(data))])
+			}
+			if got := fmt.Sprintf("%x", h.Sum(nil)); got != expected {
+				t.Errorf("%s step %d: expected %s, got %s", algo, step, expected, got)
+			}
+
+			h.Reset()
+			h.Write(data)
+			if got := fmt.Sprintf("%x", h.Sum(nil)); got != expected {
+				t.Errorf("%s after Reset: expected %s, got %s", algo, expected, got)
+			}
+		}
+	}
+}
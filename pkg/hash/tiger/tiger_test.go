@@ -0,0 +1,61 @@
+package tiger_test
+
+import (
+	"encoding/hex"
+	"hash"
+	"strings"
+	"testing"
+
+	. "github.com/TechMDW/hashit/pkg/hash/tiger"
+)
+
+// NESSIE test vectors.
+var vectors = []struct {
+	input         string
+	tiger, tiger2 string
+}{
+	{"", "3293ac630c13f0245f92bbb1766e16167a4e58492dde73f3", "4441be75f6018773c206c22745374b924aa8313fef919f41"},
+	{"abc", "2aab1484e8c158f2bfb8c5ff41b57a525129131c957b5f93", "f68d7bc5af4b43a06e048d7829560d4a9415658bb0b1f3bf"},
+	{"message digest", "d981f8cb78201a950dcf3048751e441c517fca1aa55a29f6", "e29419a1b5fa259de8005e7de75078ea81a542ef2552462d"},
+	{strings.Repeat("a", 64), "7503f313bbea92eddca90c5d3fcc4368237457df366fb76e", "5365c9d3645ce0ef37bb38f5155b88a29bc6b689ac2d848d"},
+	{strings.Repeat("a", 1000000), "6db0e2729cbead93d715c6a7d36302e9b3cee0d2bc314b41", "e068281f060f551628cc5715b9d0226796914d45f7717cf4"},
+}
+
+func sum(h hash.Hash, s string) string {
+	h.Write([]byte(s))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func TestVectors(t *testing.T) {
+	for _, v := range vectors {
+		name := v.input
+		if len(name) > 16 {
+			name = name[:16] + "..."
+		}
+
+		if got := sum(New(), v.input); got != v.tiger {
+			t.Errorf("Tiger(%q) = %s, expected %s", name, got, v.tiger)
+		}
+		if got := sum(New2(), v.input); got != v.tiger2 {
+			t.Errorf("Tiger2(%q) = %s, expected %s", name, got, v.tiger2)
+		}
+	}
+}
+
+func TestStreaming(t *testing.T) {
+	data := strings.Repeat("The quick brown fox jumps over the lazy dog. ", 10)
+	expected := sum(New(), data)
+
+	h := New()
+	for i := 0; i < len(data); i += 7 {
+		h.Write([]byte(data[i:min(i+7, len(data))]))
+	}
+	if got := hex.EncodeToString(h.Sum(nil)); got != expected {
+		t.Errorf("chunked Tiger = %s, expected %s", got, expected)
+	}
+
+	h.Reset()
+	if got := sum(h, "abc"); got != vectors[1].tiger {
+		t.Errorf("Tiger after Reset = %s, expected %s", got, vectors[1].tiger)
+	}
+}
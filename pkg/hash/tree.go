@@ -0,0 +1,383 @@
+package hash
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TreeFormat selects how HashTree combines file digests into a single
+// digest for a directory.
+type TreeFormat int
+
+const (
+	// TreeHashit is the canonical hashit encoding. Every file contributes
+	// one line, in byte-wise order of its slash-separated path relative to
+	// the directory:
+	//
+	//	<hex digest of content>  <path>\n
+	//
+	// or, when modes are included,
+	//
+	//	<hex digest of content> <mode> <path>\n
+	//
+	// where mode is 100644, 100755 (owner execute bit set) or 120000 (symbolic
+	// link, hashed by its target path). The tree digest is the digest of all
+	// lines concatenated, using the same algorithm as for file contents.
+	// Directories themselves, timestamps and ownership are not included, and
+	// paths containing newlines are rejected. With SHA-256, no modes and
+	// symbolic links followed, as dirhash does, the result equals the Go
+	// dirhash "h1:" digest.
+	TreeHashit TreeFormat = iota
+	// TreeDirhash is the "h1:" format of golang.org/x/mod/sumdb/dirhash, as
+	// recorded in go.sum. It always uses SHA-256. dirhash follows symbolic
+	// links, so the digests only match with SymlinkFollow.
+	TreeDirhash
+	// TreeGit computes the Git tree object ID of the directory, as printed
+	// by "git write-tree" for the same content. It uses SHA-1 unless another
+	// algorithm is given, e.g. SHA-256 for repositories using that object
+	// format. Empty directories are not represented, and .git directories
+	// are always skipped, as in Git.
+	TreeGit
+)
+
+// ParseTreeFormat parses "hashit", "dirhash" or "git".
+func ParseTreeFormat(s string) (TreeFormat, error) {
+	switch strings.ToLower(s) {
+	case "hashit":
+		return TreeHashit, nil
+	case "dirhash", "h1":
+		return TreeDirhash, nil
+	case "git":
+		return TreeGit, nil
+	default:
+		return 0, fmt.Errorf("unknown tree format: %s", s)
+	}
+}
+
+func (f TreeFormat) String() string {
+	switch f {
+	case TreeHashit:
+		return "hashit"
+	case TreeDirhash:
+		return "dirhash"
+	case TreeGit:
+		return "git"
+	default:
+		return fmt.Sprintf("TreeFormat(%d)", int(f))
+	}
+}
+
+// TreeOptions configures HashTree.
+type TreeOptions struct {
+	Format TreeFormat
+	// Algorithm hashes file contents and the tree. The default is SHA-256,
+	// or SHA-1 for TreeGit.
+	Algorithm string
+	// Modes includes file modes in the TreeHashit encoding. TreeGit always
+	// includes them and TreeDirhash never does.
+	Modes bool
+	// Prefix is prepended to every path for TreeDirhash, e.g.
+	// "golang.org/x/mod@v0.17.0" to reproduce a go.sum entry. Other formats
+	// reject it.
+	Prefix string
+	// Walk selects the files of the tree. Recursive is implied.
+	Walk WalkOptions
+	// Workers is the number of files hashed in parallel; <= 0 means one
+	// per CPU.
+	Workers int
+}
+
+// TreeHash is the digest of a directory.
+type TreeHash struct {
+	Format      string `json:"format"`
+	Algorithm   string `json:"algorithm"`
+	Files       int    `json:"files"`
+	HashBytes   []byte `json:"hashBytes"`
+	HexDigest   string `json:"hexDigest"`
+	ID          string `json:"id"`
+	Duration    int64  `json:"duration"`
+	DurationStr string `json:"durationStr"`
+}
+
+// File mode strings shared by the hashit and Git encodings.
+const (
+	modeFile       = "100644"
+	modeExecutable = "100755"
+	modeSymlink    = "120000"
+	modeTree       = "40000"
+)
+
+// HashTree computes a single digest for the directory dir from the contents
+// and relative paths of its files, independent of walk order and of the
+// platform path separator.
+func HashTree(ctx context.Context, dir string, opts TreeOptions) (*TreeHash, error) {
+	timeStart := time.Now()
+
+	algoName := opts.Algorithm
+	if algoName == "" {
+		algoName = "sha256"
+		if opts.Format == TreeGit {
+			algoName = "sha1"
+		}
+	}
+
+	algo, err := lookup(algoName)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Format == TreeDirhash && algo.Name != "sha256" {
+		return nil, fmt.Errorf("dirhash format requires sha256, got %s", algo.Name)
+	}
+	if opts.Format != TreeDirhash && opts.Prefix != "" {
+		return nil, fmt.Errorf("a path prefix requires the dirhash format")
+	}
+
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s: not a directory", dir)
+	}
+
+	walk := opts.Walk
+	walk.Recursive = true
+	// Git never records .git, so neither may a tree ID.
+	if opts.Format == TreeGit {
+		walk.Exclude = append(slices.Clone(walk.Exclude), ".git")
+	}
+	files, err := CollectFiles(ctx, []string{dir}, walk)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Rel < files[j].Rel
+	})
+	for _, f := range files {
+		if strings.ContainsAny(f.Rel, "\n") {
+			return nil, fmt.Errorf("%s: file names with newlines are not supported", f.Path)
+		}
+	}
+
+	digests, err := hashTreeFiles(ctx, files, algo, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var sum []byte
+	switch opts.Format {
+	case TreeHashit, TreeDirhash:
+		sum = hashitTreeSum(files, digests, algo, opts)
+	case TreeGit:
+		sum = gitTreeSum(files, digests, algo)
+	default:
+		return nil, fmt.Errorf("unknown tree format: %d", opts.Format)
+	}
+
+	th := &TreeHash{
+		Format:    opts.Format.String(),
+		Algorithm: algo.Name,
+		Files:     len(files),
+		HashBytes: sum,
+		HexDigest: fmt.Sprintf("%x", sum),
+	}
+
+	th.ID = th.HexDigest
+	if opts.Format == TreeDirhash {
+		th.ID = "h1:" + base64.StdEncoding.EncodeToString(sum)
+	}
+
+	timeSince := time.Since(timeStart)
+	th.Duration = timeSince.Milliseconds()
+	th.DurationStr = timeSince.String()
+
+	return th, nil
+}
+
+// hashTreeFiles returns the content digest of every file. For TreeGit the
+// contents are hashed as Git blob objects. Once ctx is done it stops with a
+// CanceledError counting the files and bytes hashed so far.
+func hashTreeFiles(ctx context.Context, files []FileEntry, algo *Algorithm, opts TreeOptions) ([][]byte, error) {
+	ctx = withoutProgress(ctx)
+	digests := make([][]byte, len(files))
+	sizes := make([]int64, len(files))
+	errs := make([]error, len(files))
+
+	forEachParallel(ctx, len(files), opts.Workers, func(i int) {
+		digests[i], sizes[i], errs[i] = hashTreeFile(ctx, files[i], algo, opts.Format == TreeGit)
+	})
+
+	if ctx.Err() != nil {
+		cerr := &CanceledError{Err: context.Cause(ctx)}
+		for i, err := range errs {
+			var partial *CanceledError
+			switch {
+			case errors.As(err, &partial):
+				cerr.Processed += partial.Processed
+			case err == nil && digests[i] != nil:
+				cerr.Files++
+				cerr.Processed += sizes[i]
+			}
+		}
+		return nil, cerr
+	}
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", files[i].Path, err)
+		}
+	}
+
+	return digests, nil
+}
+
+// hashTreeFile returns the content digest and the size of a file.
+func hashTreeFile(ctx context.Context, f FileEntry, algo *Algorithm, gitBlob bool) ([]byte, int64, error) {
+	h := algo.New()
+
+	var r io.Reader
+	var size int64
+	if f.Link != "" {
+		r = strings.NewReader(f.Link)
+		size = int64(len(f.Link))
+	} else {
+		file, err := os.Open(f.OSPath)
+		if err != nil {
+			return nil, 0, err
+		}
+		defer file.Close()
+
+		info, err := file.Stat()
+		if err != nil {
+			return nil, 0, err
+		}
+
+		r = file
+		size = info.Size()
+	}
+
+	if gitBlob {
+		fmt.Fprintf(h, "blob %d\x00", size)
+	}
+
+	if err := writeReader(ctx, r, []hash.Hash{h}); err != nil {
+		return nil, 0, err
+	}
+
+	return h.Sum(nil), size, nil
+}
+
+// treeMode returns the Git-style mode string of a file.
+func treeMode(f FileEntry) string {
+	switch {
+	case f.Mode&fs.ModeSymlink != 0:
+		return modeSymlink
+	// Git only looks at the owner execute bit.
+	case f.Mode&0100 != 0:
+		return modeExecutable
+	default:
+		return modeFile
+	}
+}
+
+func hashitTreeSum(files []FileEntry, digests [][]byte, algo *Algorithm, opts TreeOptions) []byte {
+	h := algo.New()
+	for i, f := range files {
+		name := f.Rel
+		if opts.Format == TreeDirhash && opts.Prefix != "" {
+			name = opts.Prefix + "/" + name
+		}
+
+		if opts.Format == TreeHashit && opts.Modes {
+			fmt.Fprintf(h, "%x %s %s\n", digests[i], treeMode(f), name)
+		} else {
+			fmt.Fprintf(h, "%x  %s\n", digests[i], name)
+		}
+	}
+
+	return h.Sum(nil)
+}
+
+// gitTree is a directory of a Git tree being assembled.
+type gitTree struct {
+	entries map[string]*gitTreeEntry
+}
+
+type gitTreeEntry struct {
+	mode    string
+	id      []byte
+	subtree *gitTree
+}
+
+func gitTreeSum(files []FileEntry, digests [][]byte, algo *Algorithm) []byte {
+	root := &gitTree{entries: map[string]*gitTreeEntry{}}
+	for i, f := range files {
+		dir := root
+		parts := strings.Split(f.Rel, "/")
+		for _, part := range parts[:len(parts)-1] {
+			entry, ok := dir.entries[part]
+			if !ok {
+				entry = &gitTreeEntry{
+					mode:    modeTree,
+					subtree: &gitTree{entries: map[string]*gitTreeEntry{}},
+				}
+				dir.entries[part] = entry
+			}
+			dir = entry.subtree
+		}
+
+		dir.entries[parts[len(parts)-1]] = &gitTreeEntry{mode: treeMode(f), id: digests[i]}
+	}
+
+	return root.sum(algo)
+}
+
+// sum returns the object ID of the tree. Entries are ordered as Git does,
+// comparing directory names as if they ended in a slash.
+func (t *gitTree) sum(algo *Algorithm) []byte {
+	names := make([]string, 0, len(t.entries))
+	for name := range t.entries {
+		names = append(names, name)
+	}
+
+	sortKey := func(name string) string {
+		if t.entries[name].subtree != nil {
+			return name + "/"
+		}
+		return name
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return sortKey(names[i]) < sortKey(names[j])
+	})
+
+	var body bytes.Buffer
+	for _, name := range names {
+		entry := t.entries[name]
+		if entry.subtree != nil {
+			entry.id = entry.subtree.sum(algo)
+		}
+
+		body.WriteString(entry.mode)
+		body.WriteByte(' ')
+		body.WriteString(name)
+		body.WriteByte(0)
+		body.Write(entry.id)
+	}
+
+	h := algo.New()
+	fmt.Fprintf(h, "tree %d\x00", body.Len())
+	h.Write(body.Bytes())
+	return h.Sum(nil)
+}
@@ -0,0 +1,196 @@
+package hash_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	. "github.com/TechMDW/hashit/pkg/hash"
+)
+
+// writeTreeFixture creates the directory the expected tree digests below
+// were computed for with git write-tree and dirhash.HashDir.
+func writeTreeFixture(t *testing.T) string {
+	t.Helper()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("the fixture needs symbolic links and execute bits")
+	}
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"a.txt":      "hello\n",
+		"b.txt":      "b.\n",
+		"b/f":        "b\n",
+		"sub/x":      "x",
+		"sub/run.sh": "exe",
+		"sub/deep/d": "d\n",
+	}
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+	}
+
+	if err := os.Chmod(filepath.Join(dir, "sub", "run.sh"), 0755); err != nil {
+		t.Fatalf("Failed to chmod: %v", err)
+	}
+	if err := os.Symlink("a.txt", filepath.Join(dir, "link")); err != nil {
+		t.Fatalf("Failed to create symlink: %v", err)
+	}
+
+	return dir
+}
+
+func TestHashTree(t *testing.T) {
+	dir := writeTreeFixture(t)
+
+	tests := []struct {
+		name     string
+		opts     TreeOptions
+		expected string
+	}{
+		{
+			name:     "git",
+			opts:     TreeOptions{Format: TreeGit, Walk: WalkOptions{Symlinks: SymlinkTarget}},
+			expected: "a6d90a58c60df1ddacb18b62647f1299f5fc60f4",
+		},
+		{
+			name:     "dirhash",
+			opts:     TreeOptions{Format: TreeDirhash, Walk: WalkOptions{Symlinks: SymlinkFollow}},
+			expected: "h1:KXf/vKtyWUW5hm95rrahzxjNSWDnB5+zpAdoMTYmeyQ=",
+		},
+		{
+			name:     "dirhash prefix",
+			opts:     TreeOptions{Format: TreeDirhash, Prefix: "ex.com/m@v1", Walk: WalkOptions{Symlinks: SymlinkFollow}},
+			expected: "h1:hD4A9ixMCnRR/VUvKO7PLWcbuE7XjC+5wzJPgb88K24=",
+		},
+		{
+			// The hashit encoding without modes matches dirhash.
+			name:     "hashit",
+			opts:     TreeOptions{Format: TreeHashit, Walk: WalkOptions{Symlinks: SymlinkFollow}},
+			expected: "2977ffbcab725945b9866f79aeb6a1cf18cd4960e7079fb3a407683136267b24",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			th, err := HashTree(context.Background(), dir, tt.opts)
+			if err != nil {
+				t.Fatalf("HashTree failed: %v", err)
+			}
+
+			if th.ID != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, th.ID)
+			}
+			if th.Files != 7 {
+				t.Errorf("Expected 7 files, got %d", th.Files)
+			}
+		})
+	}
+}
+
+func TestHashTreeModes(t *testing.T) {
+	dir := writeTreeFixture(t)
+	opts := TreeOptions{Walk: WalkOptions{Symlinks: SymlinkTarget}}
+
+	plain, err := HashTree(context.Background(), dir, opts)
+	if err != nil {
+		t.Fatalf("HashTree failed: %v", err)
+	}
+
+	opts.Modes = true
+	withModes, err := HashTree(context.Background(), dir, opts)
+	if err != nil {
+		t.Fatalf("HashTree failed: %v", err)
+	}
+
+	if err := os.Chmod(filepath.Join(dir, "sub", "run.sh"), 0644); err != nil {
+		t.Fatalf("Failed to chmod: %v", err)
+	}
+
+	changed, err := HashTree(context.Background(), dir, opts)
+	if err != nil {
+		t.Fatalf("HashTree failed: %v", err)
+	}
+
+	opts.Modes = false
+	plainChanged, err := HashTree(context.Background(), dir, opts)
+	if err != nil {
+		t.Fatalf("HashTree failed: %v", err)
+	}
+
+	if plain.ID == withModes.ID {
+		t.Error("Expected modes to change the digest")
+	}
+	if withModes.ID == changed.ID {
+		t.Error("Expected a mode change to change the digest with modes")
+	}
+	if plain.ID != plainChanged.ID {
+		t.Error("Expected a mode change not to change the digest without modes")
+	}
+}
+
+func TestHashTreeGitSkipsDotGit(t *testing.T) {
+	dir := writeTreeFixture(t)
+	for name, content := range map[string]string{".git/HEAD": "ref: refs/heads/main\n", "sub/.git": "gitdir: ../.git/modules/sub\n"} {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+	}
+
+	th, err := HashTree(context.Background(), dir, TreeOptions{Format: TreeGit, Walk: WalkOptions{Symlinks: SymlinkTarget}})
+	if err != nil {
+		t.Fatalf("HashTree failed: %v", err)
+	}
+	if th.ID != "a6d90a58c60df1ddacb18b62647f1299f5fc60f4" {
+		t.Errorf("Expected .git to be skipped, got %s", th.ID)
+	}
+}
+
+func TestHashTreeGitOwnerExecuteBit(t *testing.T) {
+	dir := writeTreeFixture(t)
+	opts := TreeOptions{Format: TreeGit, Walk: WalkOptions{Symlinks: SymlinkTarget}}
+	script := filepath.Join(dir, "sub", "run.sh")
+
+	// Git marks a file executable by the owner execute bit alone.
+	for mode, executable := range map[os.FileMode]bool{0744: true, 0645: false, 0654: false} {
+		if err := os.Chmod(script, mode); err != nil {
+			t.Fatalf("Failed to chmod: %v", err)
+		}
+
+		th, err := HashTree(context.Background(), dir, opts)
+		if err != nil {
+			t.Fatalf("HashTree failed: %v", err)
+		}
+		if got := th.ID == "a6d90a58c60df1ddacb18b62647f1299f5fc60f4"; got != executable {
+			t.Errorf("Mode %o: expected executable %v, got tree %s", mode, executable, th.ID)
+		}
+	}
+}
+
+func TestHashTreeDirhashAlgorithm(t *testing.T) {
+	_, err := HashTree(context.Background(), t.TempDir(), TreeOptions{Format: TreeDirhash, Algorithm: "md5"})
+	if err == nil {
+		t.Error("Expected error for dirhash with md5, got nil")
+	}
+}
+
+func TestHashTreePrefixFormat(t *testing.T) {
+	for _, format := range []TreeFormat{TreeHashit, TreeGit} {
+		_, err := HashTree(context.Background(), t.TempDir(), TreeOptions{Format: format, Prefix: "ex.com/m@v1"})
+		if err == nil {
+			t.Errorf("Expected error for a prefix with format %d, got nil", format)
+		}
+	}
+}
@@ -0,0 +1,266 @@
+package hash
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// SymlinkPolicy controls how symbolic links found while walking a
+// directory are treated.
+type SymlinkPolicy int
+
+const (
+	// SymlinkSkip ignores symbolic links.
+	SymlinkSkip SymlinkPolicy = iota
+	// SymlinkFollow hashes the files and walks the directories links point
+	// to. Links that would form a cycle are skipped.
+	SymlinkFollow
+	// SymlinkTarget hashes the link target path instead of its content,
+	// the way Git records symbolic links.
+	SymlinkTarget
+)
+
+// ParseSymlinkPolicy parses "skip", "follow" or "target".
+func ParseSymlinkPolicy(s string) (SymlinkPolicy, error) {
+	switch strings.ToLower(s) {
+	case "skip":
+		return SymlinkSkip, nil
+	case "follow":
+		return SymlinkFollow, nil
+	case "target":
+		return SymlinkTarget, nil
+	default:
+		return 0, fmt.Errorf("unknown symlink policy: %s", s)
+	}
+}
+
+// WalkOptions configures how CollectFiles expands its arguments.
+type WalkOptions struct {
+	// Recursive walks directories. Without it directories are an error.
+	Recursive bool
+	// Include keeps only files matching at least one pattern. Patterns
+	// without a slash match the file name, others the path relative to the
+	// walked directory; "**" matches any number of directories.
+	Include []string
+	// Exclude drops files and directories matching any pattern.
+	Exclude []string
+	// GitIgnore honors .gitignore files found inside walked directories and
+	// skips .git directories.
+	GitIgnore bool
+	// Symlinks is the policy for symbolic links inside walked directories.
+	// Links given directly as arguments are always followed.
+	Symlinks SymlinkPolicy
+}
+
+// FileEntry is a file selected for hashing.
+type FileEntry struct {
+	// Path is the slash-separated path reported in manifests: the argument
+	// as given joined with the path below it.
+	Path string
+	// Rel is the slash-separated path relative to the walked directory, or
+	// the file name for files given directly.
+	Rel string
+	// OSPath is the path used to open the file.
+	OSPath string
+	// Mode holds the type and permission bits of the file. Symbolic links
+	// under SymlinkTarget have fs.ModeSymlink set.
+	Mode fs.FileMode
+	// Link is the target of a symbolic link under SymlinkTarget and empty
+	// otherwise.
+	Link string
+}
+
+// CollectFiles expands paths, which may be files, directories or glob
+// patterns, into the files to hash, sorted by Path without duplicates.
+// Walking stops with a CanceledError once ctx is done.
+func CollectFiles(ctx context.Context, paths []string, opts WalkOptions) ([]FileEntry, error) {
+	var files []FileEntry
+	for _, arg := range paths {
+		matches := []string{arg}
+		if strings.ContainsAny(arg, "*?[") {
+			var err error
+			matches, err = filepath.Glob(arg)
+			if err != nil {
+				return nil, err
+			}
+			if len(matches) == 0 {
+				return nil, fmt.Errorf("%s: no matches found", arg)
+			}
+		}
+
+		for _, match := range matches {
+			found, err := collectPath(ctx, match, opts)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, found...)
+		}
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Path < files[j].Path
+	})
+
+	// Overlapping arguments must not list a file twice.
+	unique := files[:0]
+	for i, f := range files {
+		if i == 0 || f.Path != files[i-1].Path {
+			unique = append(unique, f)
+		}
+	}
+
+	return unique, nil
+}
+
+func collectPath(ctx context.Context, name string, opts WalkOptions) ([]FileEntry, error) {
+	info, err := os.Stat(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.IsDir() {
+		return []FileEntry{{
+			Path:   filepath.ToSlash(filepath.Clean(name)),
+			Rel:    filepath.Base(name),
+			OSPath: name,
+			Mode:   info.Mode(),
+		}}, nil
+	}
+
+	if !opts.Recursive {
+		return nil, fmt.Errorf("%s: is a directory", name)
+	}
+
+	w := &walker{
+		opts:    opts,
+		root:    filepath.Clean(name),
+		visited: map[string]bool{},
+	}
+	if err := w.walk(ctx, w.root, "", nil); err != nil {
+		return nil, err
+	}
+
+	return w.files, nil
+}
+
+// walker collects the files below a single directory argument.
+type walker struct {
+	opts    WalkOptions
+	root    string
+	files   []FileEntry
+	visited map[string]bool
+}
+
+// walk adds the files in dir, whose slash-separated path relative to the
+// root is rel.
+func (w *walker) walk(ctx context.Context, dir, rel string, rules ignoreRules) error {
+	if err := canceled(ctx, 0); err != nil {
+		return err
+	}
+
+	if w.opts.Symlinks == SymlinkFollow {
+		real, err := filepath.EvalSymlinks(dir)
+		if err != nil {
+			return err
+		}
+		if w.visited[real] {
+			return nil
+		}
+		w.visited[real] = true
+		defer delete(w.visited, real)
+	}
+
+	if w.opts.GitIgnore {
+		var err error
+		if rules, err = rules.loadGitignore(dir, rel); err != nil {
+			return err
+		}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		osPath := filepath.Join(dir, entry.Name())
+		entryRel := path.Join(rel, entry.Name())
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		mode := info.Mode()
+		link := ""
+		if mode&fs.ModeSymlink != 0 {
+			switch w.opts.Symlinks {
+			case SymlinkSkip:
+				continue
+			case SymlinkTarget:
+				if link, err = os.Readlink(osPath); err != nil {
+					return err
+				}
+			case SymlinkFollow:
+				info, err := os.Stat(osPath)
+				if err != nil {
+					// Dangling links have nothing to hash.
+					continue
+				}
+				mode = info.Mode()
+			}
+		}
+
+		isDir := mode.IsDir()
+		if w.opts.GitIgnore && isDir && entry.Name() == ".git" {
+			continue
+		}
+		if rules.ignored(entryRel, isDir) || matchAny(w.opts.Exclude, entryRel) {
+			continue
+		}
+
+		if isDir {
+			if err := w.walk(ctx, osPath, entryRel, rules); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if link == "" && !mode.IsRegular() {
+			continue
+		}
+		if len(w.opts.Include) > 0 && !matchAny(w.opts.Include, entryRel) {
+			continue
+		}
+
+		w.files = append(w.files, FileEntry{
+			Path:   path.Join(filepath.ToSlash(w.root), entryRel),
+			Rel:    entryRel,
+			OSPath: osPath,
+			Mode:   mode,
+			Link:   link,
+		})
+	}
+
+	return nil
+}
+
+// matchAny reports whether rel matches one of the include/exclude patterns.
+func matchAny(patterns []string, rel string) bool {
+	for _, pattern := range patterns {
+		if !strings.Contains(pattern, "/") {
+			pattern = "**/" + pattern
+		}
+		if matchGlob(strings.TrimPrefix(pattern, "/"), rel) {
+			return true
+		}
+	}
+
+	return false
+}
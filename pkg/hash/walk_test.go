@@ -0,0 +1,177 @@
+package hash_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+
+	. "github.com/TechMDW/hashit/pkg/hash"
+)
+
+// writeTree creates the given files, relative to dir, with their names as
+// content.
+func writeTree(t *testing.T, dir string, files ...string) {
+	t.Helper()
+
+	for _, name := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(p, []byte(name), 0644); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+	}
+}
+
+func collectPaths(t *testing.T, paths []string, opts WalkOptions) []string {
+	t.Helper()
+
+	files, err := CollectFiles(context.Background(), paths, opts)
+	if err != nil {
+		t.Fatalf("CollectFiles failed: %v", err)
+	}
+
+	var result []string
+	for _, f := range files {
+		result = append(result, f.Path)
+	}
+
+	return result
+}
+
+func TestCollectFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, "b.txt", "a.txt", "sub/c.log", "sub/deep/d.txt", "z/e.txt")
+	root := filepath.ToSlash(dir)
+
+	tests := []struct {
+		name     string
+		opts     WalkOptions
+		expected []string
+	}{
+		{
+			name:     "all",
+			opts:     WalkOptions{Recursive: true},
+			expected: []string{"a.txt", "b.txt", "sub/c.log", "sub/deep/d.txt", "z/e.txt"},
+		},
+		{
+			name:     "include",
+			opts:     WalkOptions{Recursive: true, Include: []string{"*.txt"}},
+			expected: []string{"a.txt", "b.txt", "sub/deep/d.txt", "z/e.txt"},
+		},
+		{
+			name:     "exclude directory",
+			opts:     WalkOptions{Recursive: true, Exclude: []string{"deep", "/z"}},
+			expected: []string{"a.txt", "b.txt", "sub/c.log"},
+		},
+		{
+			name:     "exclude double star",
+			opts:     WalkOptions{Recursive: true, Exclude: []string{"sub/**/*.txt"}},
+			expected: []string{"a.txt", "b.txt", "sub/c.log", "z/e.txt"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var expected []string
+			for _, p := range tt.expected {
+				expected = append(expected, root+"/"+p)
+			}
+
+			paths := collectPaths(t, []string{dir}, tt.opts)
+			if !reflect.DeepEqual(paths, expected) {
+				t.Errorf("Expected %v, got %v", expected, paths)
+			}
+		})
+	}
+}
+
+func TestCollectFilesArguments(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, "a.txt", "b.txt", "c.log", "sub/d.txt")
+	root := filepath.ToSlash(dir)
+
+	paths := collectPaths(t, []string{filepath.Join(dir, "*.txt"), filepath.Join(dir, "a.txt"), filepath.Join(dir, "c.log")}, WalkOptions{})
+	expected := []string{root + "/a.txt", root + "/b.txt", root + "/c.log"}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Errorf("Expected %v, got %v", expected, paths)
+	}
+
+	if _, err := CollectFiles(context.Background(), []string{filepath.Join(dir, "sub")}, WalkOptions{}); err == nil {
+		t.Error("Expected error for directory without Recursive, got nil")
+	}
+	if _, err := CollectFiles(context.Background(), []string{filepath.Join(dir, "*.none")}, WalkOptions{}); err == nil {
+		t.Error("Expected error for glob without matches, got nil")
+	}
+}
+
+func TestCollectFilesGitIgnore(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir,
+		".git/HEAD",
+		".gitignore",
+		"a.txt",
+		"debug.log",
+		"keep.log",
+		"build/out.bin",
+		"src/main.go",
+		"src/.gitignore",
+		"src/gen/types.go",
+		"src/vendor.txt",
+		"docs/build/index.html",
+	)
+	os.WriteFile(filepath.Join(dir, ".gitignore"), []byte("# comment\n*.log\n!keep.log\n/build/\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "src", ".gitignore"), []byte("gen/\nvendor.txt\n"), 0644)
+	root := filepath.ToSlash(dir)
+
+	paths := collectPaths(t, []string{dir}, WalkOptions{Recursive: true, GitIgnore: true})
+
+	var expected []string
+	for _, p := range []string{".gitignore", "a.txt", "docs/build/index.html", "keep.log", "src/.gitignore", "src/main.go"} {
+		expected = append(expected, root+"/"+p)
+	}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Errorf("Expected %v, got %v", expected, paths)
+	}
+}
+
+func TestCollectFilesSymlinks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symbolic links need extra privileges on Windows")
+	}
+
+	dir := t.TempDir()
+	writeTree(t, dir, "a.txt", "sub/b.txt")
+	if err := os.Symlink("a.txt", filepath.Join(dir, "link.txt")); err != nil {
+		t.Fatalf("Failed to create symlink: %v", err)
+	}
+	if err := os.Symlink("..", filepath.Join(dir, "sub", "loop")); err != nil {
+		t.Fatalf("Failed to create symlink: %v", err)
+	}
+	root := filepath.ToSlash(dir)
+
+	tests := []struct {
+		policy   SymlinkPolicy
+		expected []string
+	}{
+		{SymlinkSkip, []string{"a.txt", "sub/b.txt"}},
+		{SymlinkFollow, []string{"a.txt", "link.txt", "sub/b.txt"}},
+		{SymlinkTarget, []string{"a.txt", "link.txt", "sub/b.txt", "sub/loop"}},
+	}
+
+	for _, tt := range tests {
+		var expected []string
+		for _, p := range tt.expected {
+			expected = append(expected, root+"/"+p)
+		}
+
+		paths := collectPaths(t, []string{dir}, WalkOptions{Recursive: true, Symlinks: tt.policy})
+		if !reflect.DeepEqual(paths, expected) {
+			t.Errorf("Policy %d: expected %v, got %v", tt.policy, expected, paths)
+		}
+	}
+}
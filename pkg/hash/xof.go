@@ -0,0 +1,196 @@
+package hash
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TechMDW/hashit/pkg/hash/blake3"
+	"golang.org/x/crypto/sha3"
+)
+
+// XOF is an extendable-output function. After the input is written, Read
+// returns as many output bytes as requested; Sum returns the default size.
+// Writing after the first Read is not supported.
+type XOF interface {
+	hash.Hash
+	io.Reader
+}
+
+// MaxXOFLength is the largest output length of ComputeXOF. The output is
+// held in memory twice, as bytes and as hex, so it is bounded; read longer
+// streams from NewXOF instead.
+const MaxXOFLength = 1 << 20
+
+// XOFOptions configures ComputeXOF.
+type XOFOptions struct {
+	// Length is the output length in bytes, up to MaxXOFLength. Zero
+	// selects the default size of the algorithm.
+	Length int
+	// FunctionName and Customization are the N and S strings of cSHAKE
+	// (NIST SP 800-185). With both empty cSHAKE is identical to SHAKE.
+	FunctionName  string
+	Customization string
+}
+
+func shakeXOF(security int) func(functionName, customization []byte) (XOF, error) {
+	return func(functionName, customization []byte) (XOF, error) {
+		if security == 128 {
+			return sha3.NewCShake128(functionName, customization), nil
+		}
+		return sha3.NewCShake256(functionName, customization), nil
+	}
+}
+
+func blake3XOF(functionName, customization []byte) (XOF, error) {
+	if len(functionName) != 0 || len(customization) != 0 {
+		return nil, fmt.Errorf("blake3 does not support customization strings")
+	}
+	return &blake3Reader{Hasher: blake3.New()}, nil
+}
+
+// blake3Reader adds Read to a BLAKE3 Hasher.
+type blake3Reader struct {
+	*blake3.Hasher
+	out *blake3.OutputReader
+}
+
+func (b *blake3Reader) Read(p []byte) (int, error) {
+	if b.out == nil {
+		b.out = b.XOF()
+	}
+	return b.out.Read(p)
+}
+
+func (b *blake3Reader) Reset() {
+	b.Hasher.Reset()
+	b.out = nil
+}
+
+// NewXOF returns the named extendable-output function, with the cSHAKE
+// function name and customization strings of opts if any.
+func NewXOF(hashType string, opts XOFOptions) (XOF, error) {
+	_, x, err := newXOF(hashType, opts)
+	return x, err
+}
+
+// newXOF returns the named extendable-output function and its canonical
+// name. SHAKE with a function name or customization string is cSHAKE and
+// named so, e.g. "cshake128".
+func newXOF(hashType string, opts XOFOptions) (string, XOF, error) {
+	algo, err := lookup(hashType)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if algo.NewXOF == nil {
+		return "", nil, fmt.Errorf("hash type %s is not an extendable-output function", algo.Name)
+	}
+
+	name := algo.Name
+	if algo.Family == "sha3" && (opts.FunctionName != "" || opts.Customization != "") {
+		name = "c" + name
+	}
+
+	x, err := algo.NewXOF([]byte(opts.FunctionName), []byte(opts.Customization))
+	return name, x, err
+}
+
+// ComputeXOF returns opts.Length bytes of output of the named
+// extendable-output function (shake128, shake256 or blake3) for the data.
+func ComputeXOF(ctx context.Context, data []byte, hashType string, opts XOFOptions, file bool) (*GenericHash, error) {
+	if !file {
+		return computeXOF(ctx, bytes.NewReader(data), data, hashType, opts)
+	}
+
+	f, err := os.Open(string(data))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return computeXOF(ctx, f, data, hashType, opts)
+}
+
+// ComputeXOFReader returns opts.Length bytes of output of the named
+// extendable-output function for everything read from r.
+func ComputeXOFReader(ctx context.Context, r io.Reader, hashType string, opts XOFOptions) (*GenericHash, error) {
+	return computeXOF(ctx, r, nil, hashType, opts)
+}
+
+func computeXOF(ctx context.Context, r io.Reader, input []byte, hashType string, opts XOFOptions) (*GenericHash, error) {
+	timeStart := time.Now()
+
+	if opts.Length < 0 || opts.Length > MaxXOFLength {
+		return &GenericHash{}, fmt.Errorf("invalid output length %d: at most %d bytes are supported", opts.Length, MaxXOFLength)
+	}
+
+	name, x, err := newXOF(hashType, opts)
+	if err != nil {
+		return &GenericHash{}, err
+	}
+
+	if err := writeReader(ctx, r, []hash.Hash{x}); err != nil {
+		return nil, err
+	}
+
+	length := opts.Length
+	if length == 0 {
+		length = x.Size()
+	}
+
+	gh := &GenericHash{Input: input, Algorithm: name}
+	gh.HashBytes = make([]byte, length)
+	x.Read(gh.HashBytes)
+	gh.HexDigest = fmt.Sprintf("%x", gh.HashBytes)
+	gh.Size = length
+	timeSince := time.Since(timeStart)
+	gh.Duration = timeSince.Milliseconds()
+	gh.DurationStr = timeSince.String()
+
+	return gh, nil
+}
+
+// ParseLength parses an output length given in bytes ("64") or bits
+// ("512bits"), up to MaxXOFLength bytes. Bit lengths must be a multiple of
+// 8.
+func ParseLength(length string) (int, error) {
+	s := strings.ToLower(strings.TrimSpace(length))
+
+	bits := false
+	for _, suffix := range []string{"bits", "bit"} {
+		if strings.HasSuffix(s, suffix) {
+			s, bits = strings.TrimSuffix(s, suffix), true
+			break
+		}
+	}
+	for _, suffix := range []string{"bytes", "byte"} {
+		if !bits && strings.HasSuffix(s, suffix) {
+			s = strings.TrimSuffix(s, suffix)
+			break
+		}
+	}
+
+	n, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid length %q", length)
+	}
+
+	if bits {
+		if n%8 != 0 {
+			return 0, fmt.Errorf("length of %d bits is not a whole number of bytes", n)
+		}
+		n /= 8
+	}
+	if n > MaxXOFLength {
+		return 0, fmt.Errorf("length %q exceeds the maximum of %d bytes", length, MaxXOFLength)
+	}
+
+	return n, nil
+}
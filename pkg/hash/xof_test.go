@@ -0,0 +1,138 @@
+package hash_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	. "github.com/TechMDW/hashit/pkg/hash"
+)
+
+func TestComputeXOFLength(t *testing.T) {
+	// Long outputs from Python's hashlib.
+	expected := map[string]string{
+		"shake128": "ae3bdcf04986a8e7ddd99ac948254693fc32ca6ce3ed278c0c54127f072ba21e977d76aa76cab8f85f61c3e1fb7dab42c6b96d39f96fbd8cdcba7121e28cc97b",
+		"shake256": "be15253026b9a85e01ae54b1939284e8e514fbdad2a3bd5c1c0f437e60548e262dd68c2a2f932847f9610eeb51f8ba1a180ca878c788e900d899538d45c9c4a6f1bf10d8502a7ccbd9fd540bd856591000700e10130673ef970ffb788afe08426648a216",
+	}
+
+	for name, hex := range expected {
+		gh, err := ComputeXOF(context.Background(), []byte("test data"), name, XOFOptions{Length: len(hex) / 2}, false)
+		if err != nil {
+			t.Fatalf("ComputeXOF(%s) failed: %v", name, err)
+		}
+		if gh.HexDigest != hex {
+			t.Errorf("%s: expected %s, got %s", name, hex, gh.HexDigest)
+		}
+		if gh.Size != len(hex)/2 || gh.Algorithm != name {
+			t.Errorf("%s: labeled %s with size %d", name, gh.Algorithm, gh.Size)
+		}
+	}
+
+	for name, key := range map[string]string{"shake128": "Shake128", "shake256": "Shake256", "blake3": "blake3"} {
+		short, err := ComputeXOF(context.Background(), []byte("test data"), name, XOFOptions{}, false)
+		if err != nil {
+			t.Fatalf("ComputeXOF(%s) failed: %v", name, err)
+		}
+		if short.HexDigest != expectedHashesMap[key] {
+			t.Errorf("%s default length: expected %s, got %s", name, expectedHashesMap[key], short.HexDigest)
+		}
+
+		long, err := ComputeXOF(context.Background(), []byte("test data"), name, XOFOptions{Length: 1000}, false)
+		if err != nil {
+			t.Fatalf("ComputeXOF(%s) failed: %v", name, err)
+		}
+		if !strings.HasPrefix(long.HexDigest, short.HexDigest) {
+			t.Errorf("%s: long output does not extend the default digest", name)
+		}
+	}
+}
+
+func TestComputeXOFCustomization(t *testing.T) {
+	// Samples from the NIST SP 800-185 cSHAKE examples.
+	tests := []struct {
+		name     string
+		length   int
+		expected string
+	}{
+		{"cshake128", 32, "c1c36925b6409a04f1b504fcbca9d82b4017277cb5ed2b2065fc1d3814d5aaf5"},
+		{"cshake256", 64, "d008828e2b80ac9d2218ffee1d070c48b8e4c87bff32c9699d5b6896eee0edd164020e2be0560858d9c00c037e34a96937c561a74c412bb4c746469527281c8c"},
+	}
+
+	for _, test := range tests {
+		gh, err := ComputeXOF(context.Background(), []byte{0, 1, 2, 3}, test.name, XOFOptions{Length: test.length, Customization: "Email Signature"}, false)
+		if err != nil {
+			t.Fatalf("ComputeXOF(%s) failed: %v", test.name, err)
+		}
+		if gh.HexDigest != test.expected {
+			t.Errorf("%s: expected %s, got %s", test.name, test.expected, gh.HexDigest)
+		}
+		if gh.Algorithm != test.name {
+			t.Errorf("Expected algorithm %s, got %s", test.name, gh.Algorithm)
+		}
+	}
+
+	// Without strings cSHAKE is SHAKE and labelled so.
+	gh, err := ComputeXOF(context.Background(), []byte("test data"), "cshake128", XOFOptions{}, false)
+	if err != nil || gh.Algorithm != "shake128" {
+		t.Errorf("Expected shake128, got %+v, %v", gh, err)
+	}
+
+	if _, err := ComputeXOF(context.Background(), []byte("test data"), "blake3", XOFOptions{Customization: "x"}, false); err == nil {
+		t.Error("Expected error for blake3 with a customization string, got nil")
+	}
+	if _, err := ComputeXOF(context.Background(), []byte("test data"), "sha256", XOFOptions{Length: 64}, false); err == nil {
+		t.Error("Expected error for sha256, got nil")
+	}
+}
+
+func TestParseLength(t *testing.T) {
+	tests := map[string]int{"64": 64, "512bits": 64, "512 bits": 64, "8bit": 1, "100bytes": 100, "32B": 0}
+
+	for input, expected := range tests {
+		n, err := ParseLength(input)
+		if expected == 0 {
+			if err == nil {
+				t.Errorf("ParseLength(%q): expected error, got %d", input, n)
+			}
+			continue
+		}
+		if err != nil || n != expected {
+			t.Errorf("ParseLength(%q) = %d, %v; expected %d", input, n, err, expected)
+		}
+	}
+
+	for _, input := range []string{"", "0", "-8", "12bits", "abc", "1048577", "9999999999999bits"} {
+		if _, err := ParseLength(input); err == nil {
+			t.Errorf("ParseLength(%q): expected error, got nil", input)
+		}
+	}
+}
+
+func TestComputeXOFMaxLength(t *testing.T) {
+	gh, err := ComputeXOF(context.Background(), []byte("test data"), "shake128", XOFOptions{Length: MaxXOFLength}, false)
+	if err != nil || len(gh.HashBytes) != MaxXOFLength {
+		t.Fatalf("Expected %d bytes of output, got %v", MaxXOFLength, err)
+	}
+
+	for _, length := range []int{-1, MaxXOFLength + 1} {
+		if _, err := ComputeXOF(context.Background(), []byte("test data"), "shake128", XOFOptions{Length: length}, false); err == nil {
+			t.Errorf("Expected an error for an output length of %d", length)
+		}
+	}
+}
+
+func TestXOFJSONSize(t *testing.T) {
+	gh, err := ComputeXOF(context.Background(), []byte("test data"), "shake256", XOFOptions{Length: 100}, false)
+	if err != nil {
+		t.Fatalf("ComputeXOF failed: %v", err)
+	}
+
+	j, err := json.Marshal(gh)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if !strings.Contains(string(j), `"algorithm":"shake256","size":100,`) {
+		t.Errorf("Unexpected JSON: %s", j)
+	}
+}
@@ -0,0 +1,159 @@
+package hash
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"math"
+	"math/bits"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/zeebo/xxh3"
+)
+
+// NewXXH32 returns a 32-bit xxHash (XXH32) with the given seed. Sum appends
+// the canonical big-endian digest, as printed by xxhsum.
+func NewXXH32(seed uint32) hash.Hash32 {
+	d := &xxh32{seed: seed}
+	d.Reset()
+	return d
+}
+
+// NewXXH64 returns a 64-bit xxHash (XXH64) with the given seed.
+func NewXXH64(seed uint64) hash.Hash64 {
+	return xxhash.NewWithSeed(seed)
+}
+
+// NewXXH3 returns a 64-bit XXH3 with the given seed.
+func NewXXH3(seed uint64) hash.Hash64 {
+	return xxh3.NewSeed(seed)
+}
+
+// NewXXH128 returns a 128-bit XXH3 (XXH128) with the given seed. Sum
+// appends the high 64 bits first, as printed by xxhsum.
+func NewXXH128(seed uint64) hash.Hash {
+	return xxh128{xxh3.NewSeed(seed)}
+}
+
+// seeded32 adapts NewXXH32 to Algorithm.NewSeeded, rejecting seeds that do
+// not fit in 32 bits.
+func seeded32(seed uint64) (hash.Hash, error) {
+	if seed > math.MaxUint32 {
+		return nil, fmt.Errorf("seed %d does not fit in 32 bits", seed)
+	}
+	return NewXXH32(uint32(seed)), nil
+}
+
+type xxh128 struct {
+	*xxh3.Hasher
+}
+
+func (h xxh128) Size() int { return 16 }
+
+func (h xxh128) Sum(b []byte) []byte {
+	sum := h.Sum128().Bytes()
+	return append(b, sum[:]...)
+}
+
+const (
+	xxh32Prime1 uint32 = 2654435761
+	xxh32Prime2 uint32 = 2246822519
+	xxh32Prime3 uint32 = 3266489917
+	xxh32Prime4 uint32 = 668265263
+	xxh32Prime5 uint32 = 374761393
+)
+
+// xxh32 implements XXH32 as specified in the xxHash repository's
+// doc/xxhash_spec.md.
+type xxh32 struct {
+	seed  uint32
+	v     [4]uint32
+	total uint64
+	buf   [16]byte
+	n     int
+}
+
+func (d *xxh32) Size() int      { return 4 }
+func (d *xxh32) BlockSize() int { return 16 }
+
+func (d *xxh32) Reset() {
+	d.v = [4]uint32{
+		d.seed + xxh32Prime1 + xxh32Prime2,
+		d.seed + xxh32Prime2,
+		d.seed,
+		d.seed - xxh32Prime1,
+	}
+	d.total = 0
+	d.n = 0
+}
+
+func xxh32Round(acc, lane uint32) uint32 {
+	acc += lane * xxh32Prime2
+	acc = bits.RotateLeft32(acc, 13)
+	return acc * xxh32Prime1
+}
+
+func (d *xxh32) stripe(b []byte) {
+	d.v[0] = xxh32Round(d.v[0], binary.LittleEndian.Uint32(b[0:]))
+	d.v[1] = xxh32Round(d.v[1], binary.LittleEndian.Uint32(b[4:]))
+	d.v[2] = xxh32Round(d.v[2], binary.LittleEndian.Uint32(b[8:]))
+	d.v[3] = xxh32Round(d.v[3], binary.LittleEndian.Uint32(b[12:]))
+}
+
+func (d *xxh32) Write(p []byte) (int, error) {
+	n := len(p)
+	d.total += uint64(n)
+
+	if d.n > 0 {
+		c := copy(d.buf[d.n:], p)
+		d.n += c
+		p = p[c:]
+		if d.n < len(d.buf) {
+			return n, nil
+		}
+		d.stripe(d.buf[:])
+		d.n = 0
+	}
+
+	for len(p) >= 16 {
+		d.stripe(p)
+		p = p[16:]
+	}
+	d.n = copy(d.buf[:], p)
+
+	return n, nil
+}
+
+func (d *xxh32) Sum32() uint32 {
+	var acc uint32
+	if d.total >= 16 {
+		acc = bits.RotateLeft32(d.v[0], 1) + bits.RotateLeft32(d.v[1], 7) +
+			bits.RotateLeft32(d.v[2], 12) + bits.RotateLeft32(d.v[3], 18)
+	} else {
+		acc = d.seed + xxh32Prime5
+	}
+	acc += uint32(d.total)
+
+	p := d.buf[:d.n]
+	for len(p) >= 4 {
+		acc += binary.LittleEndian.Uint32(p) * xxh32Prime3
+		acc = bits.RotateLeft32(acc, 17) * xxh32Prime4
+		p = p[4:]
+	}
+	for _, b := range p {
+		acc += uint32(b) * xxh32Prime5
+		acc = bits.RotateLeft32(acc, 11) * xxh32Prime1
+	}
+
+	acc ^= acc >> 15
+	acc *= xxh32Prime2
+	acc ^= acc >> 13
+	acc *= xxh32Prime3
+	acc ^= acc >> 16
+
+	return acc
+}
+
+func (d *xxh32) Sum(b []byte) []byte {
+	return binary.BigEndian.AppendUint32(b, d.Sum32())
+}
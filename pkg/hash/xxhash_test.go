@@ -0,0 +1,88 @@
+package hash_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	. "github.com/TechMDW/hashit/pkg/hash"
+)
+
+const xxhPrime32 = 2654435761
+
+// xxhSanityBuffer returns the buffer used by the reference xxhsum sanity
+// checks.
+func xxhSanityBuffer(n int) []byte {
+	buf := make([]byte, n)
+	gen := uint64(xxhPrime32)
+	for i := range buf {
+		buf[i] = byte(gen >> 56)
+		gen *= 11400714785074694797
+	}
+	return buf
+}
+
+// xxhVectors are from xsum_sanity_check.c in the xxHash repository.
+var xxhVectors = []struct {
+	len    int
+	seed   uint64
+	xxh32  string
+	xxh64  string
+	xxh3   string
+	xxh128 string
+}{
+	{0, 0, "02cc5d05", "ef46db3751d8e999", "2d06800538d394c2", "99aa06d3014798d86001c324468d497f"},
+	{0, xxhPrime32, "36b78ae7", "ac75fda2929b17ef", "f702ca3814de2125", "92220ae55e14ab505444f7869c671ab0"},
+	{1, 0, "cf65b03e", "e934a84adb052768", "c44bdff4074eecdb", "a6cd5e9392000f6ac44bdff4074eecdb"},
+	{1, xxhPrime32, "b4545aa4", "5014607643a9b4c3", "b53d5557e7f76f8d", "89b99554ba22467cb53d5557e7f76f8d"},
+	{14, 0, "1208e7e2", "8282dcc4994e35c8", "1ac0bbda2b9fcf03", "b2623398aa0bda1e352b80797cda6247"},
+	{14, xxhPrime32, "6af1d1fe", "c3bd6bf63deb6df0", "ce92aa806b1b4e19", "269307bcf6ef20ad50a29c20d77793f9"},
+	{222, 0, "5bd11dbd", "b641ae8cb691c174", "b9163b558664d356", "337e09641b948717f1aebd597cec6b3a"},
+	{222, xxhPrime32, "58803c5f", "20cb8ab7ae10c14a", "dffb26f42c7766e5", "91820016621e97f1ae995bb8af917a8d"},
+}
+
+func TestXXHashVectors(t *testing.T) {
+	for _, v := range xxhVectors {
+		data := xxhSanityBuffer(v.len)
+
+		for name, expected := range map[string]string{
+			"xxh32": v.xxh32, "xxh64": v.xxh64, "xxh3": v.xxh3, "xxh128": v.xxh128,
+		} {
+			gh, err := ComputeHashSeed(context.Background(), data, name, v.seed, false)
+			if err != nil {
+				t.Fatalf("ComputeHashSeed(%s) failed: %v", name, err)
+			}
+			if gh.HexDigest != expected {
+				t.Errorf("%s(len=%d, seed=%d): expected %s, got %s", name, v.len, v.seed, expected, gh.HexDigest)
+			}
+		}
+	}
+}
+
+func TestXXH32Streaming(t *testing.T) {
+	data := xxhSanityBuffer(222)
+
+	for _, step := range []int{1, 3, 15, 16, 17, 100} {
+		h := NewXXH32(xxhPrime32)
+		for i := 0; i < len(data); i += step {
+			end := i + step
+			if end > len(data) {
+				end = len(data)
+			}
+			h.Write(data[i:end])
+		}
+
+		if got := fmt.Sprintf("%08x", h.Sum32()); got != "58803c5f" {
+			t.Errorf("step %d: expected 58803c5f, got %s", step, got)
+		}
+	}
+}
+
+func TestComputeHashSeedUnsupported(t *testing.T) {
+	if _, err := ComputeHashSeed(context.Background(), []byte("test data"), "sha256", 1, false); err == nil {
+		t.Error("Expected error for sha256, got nil")
+	}
+	if _, err := ComputeHashSeed(context.Background(), []byte("test data"), "xxh32", 1<<32, false); err == nil {
+		t.Error("Expected error for a 64-bit XXH32 seed, got nil")
+	}
+}